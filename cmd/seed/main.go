@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/ladderseeker/gin-crud-starter/internal/database"
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
 	"github.com/ladderseeker/gin-crud-starter/internal/model"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
 	"os"
@@ -34,7 +35,11 @@ func main() {
 
 	// Initialize logger
 	logger.Initialize(config.Logging.Level)
-	defer logger.GetLogger().Sync()
+	defer func() {
+		if err := logger.SafeSync(); err != nil {
+			logger.Error("Failed to sync logger", zap.Error(err))
+		}
+	}()
 
 	// Connect to database
 	database, err := database.NewPostgresDB(&config.Database)
@@ -43,7 +48,7 @@ func main() {
 	}
 
 	// Auto migrate database schemas
-	if err := autoMigrate(database); err != nil {
+	if err := migration.AutoMigrate(database); err != nil {
 		logger.Fatal("Failed to migrate database schemas", zap.Error(err))
 	}
 
@@ -55,24 +60,6 @@ func main() {
 	logger.Info("Test data seeded successfully")
 }
 
-// autoMigrate migrates database schemas
-func autoMigrate(database *gorm.DB) error {
-	// List of entities to migrate
-	entities := []interface{}{
-		&model.User{},
-		// Add more entities here as needed
-	}
-
-	// Migrate entities
-	for _, entity := range entities {
-		if err := database.AutoMigrate(entity); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // seedTestData seeds the database with test data
 func seedTestData(database *gorm.DB) error {
 	// Define test users
@@ -115,7 +102,16 @@ func seedTestData(database *gorm.DB) error {
 	}
 
 	// Clear existing data
-	if err := database.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE").Error; err != nil {
+	if err := database.Exec("TRUNCATE TABLE users, roles RESTART IDENTITY CASCADE").Error; err != nil {
+		return err
+	}
+
+	// Create roles
+	testRoles := []model.Role{
+		{Name: model.RoleAdmin, Description: "Full access to all resources"},
+		{Name: model.RoleUser, Description: "Standard access"},
+	}
+	if err := database.Create(&testRoles).Error; err != nil {
 		return err
 	}
 
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/ladderseeker/gin-crud-starter/internal/database"
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+	"gorm.io/gorm"
+)
+
+// runWithTimeout runs step and gives up when ctx is done.
+// An abandoned step keeps running in the background, callers are expected to exit the process.
+func runWithTimeout[T any](ctx context.Context, step func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := step()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, fmt.Errorf("startup timed out: %w", ctx.Err())
+	}
+}
+
+// setupDatabase connects to the database, migrates its schemas and seeds the default roles
+func setupDatabase(conf *config.Config) (*gorm.DB, error) {
+	// Connect to database
+	db, err := database.NewPostgresDB(&conf.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Capture SQL per request for logging
+	if conf.Logging.LogSQL {
+		if err := database.EnableSQLCapture(db); err != nil {
+			return nil, fmt.Errorf("failed to enable SQL capture: %w", err)
+		}
+	}
+
+	// Auto migrate database schemas
+	if err := migration.AutoMigrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database schemas: %w", err)
+	}
+
+	// Ensure default roles exist
+	if err := ensureDefaultRoles(db); err != nil {
+		return nil, fmt.Errorf("failed to create default roles: %w", err)
+	}
+	return db, nil
+}
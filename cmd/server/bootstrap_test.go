@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSetupDatabaseTimeout(t *testing.T) {
+	// Start a database that accepts connections but never answers
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				for _, c := range conns {
+					_ = c.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	conf := &config.Config{Database: config.DatabaseConfig{
+		Host: host, Port: port, User: "postgres", Password: "postgres", DBName: "gin_crud", SSLMode: "disable",
+	}}
+
+	// Bootstrap against it
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	db, err := runWithTimeout(ctx, func() (*gorm.DB, error) { return setupDatabase(conf) })
+
+	// Assert bootstrap gave up at the deadline
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, db)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestRunWithTimeoutReturnsResult(t *testing.T) {
+	value, err := runWithTimeout(context.Background(), func() (int, error) { return 42, nil })
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
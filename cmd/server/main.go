@@ -1,8 +1,8 @@
 package main
 
 import (
+	"context"
 	"github.com/ladderseeker/gin-crud-starter/config"
-	"github.com/ladderseeker/gin-crud-starter/internal/database"
 	"github.com/ladderseeker/gin-crud-starter/internal/model"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
 	"go.uber.org/zap"
@@ -10,31 +10,30 @@ import (
 )
 
 func main() {
+	// Bound the whole bootstrap so an unreachable dependency fails the deploy instead of hanging it
+	ctx, cancel := context.WithTimeout(context.Background(), config.StartupTimeout())
+	defer cancel()
+
 	// Load configuration
-	conf, err := config.LoadConfig()
+	conf, err := runWithTimeout(ctx, config.LoadConfig)
 	if err != nil {
 		panic("Failed to load configuration: " + err.Error())
 	}
 
 	// Initialize logger
 	logger.Initialize(conf.Logging.Level)
-	defer func(logger *zap.Logger) {
-		err := logger.Sync()
-		if err != nil {
+	defer func() {
+		if err := logger.SafeSync(); err != nil {
 			logger.Error("Failed to sync logger", zap.Error(err))
 		}
-	}(logger.GetLogger())
+	}()
 
-	// Connect to database
-	db, err := database.NewPostgresDB(&conf.Database)
+	// Connect to and prepare the database
+	db, err := runWithTimeout(ctx, func() (*gorm.DB, error) { return setupDatabase(conf) })
 	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
-	}
-
-	// Auto migrate database schemas
-	if err := autoMigrate(db); err != nil {
-		logger.Fatal("Failed to migrate database schemas", zap.Error(err))
+		logger.Fatal("Failed to set up database", zap.Error(err))
 	}
+	cancel()
 
 	// Create and start server
 	server := NewServer(conf, db)
@@ -43,20 +42,13 @@ func main() {
 	}
 }
 
-// autoMigrate migrates database schemas
-func autoMigrate(db *gorm.DB) error {
-	// List of entities to migrate
-	entities := []interface{}{
-		&model.User{},
-		// Add more entities here
-	}
-
-	// Migrate entities
-	for _, entity := range entities {
-		if err := db.AutoMigrate(entity); err != nil {
+// ensureDefaultRoles creates the built-in roles if they are missing
+func ensureDefaultRoles(db *gorm.DB) error {
+	for _, name := range []string{model.RoleAdmin, model.RoleUser} {
+		role := model.Role{Name: name}
+		if err := db.Where(model.Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
@@ -2,8 +2,15 @@ package main
 
 import (
 	"context"
+	"github.com/ladderseeker/gin-crud-starter/internal/jobs"
+	"github.com/ladderseeker/gin-crud-starter/internal/middleware"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
 	"github.com/ladderseeker/gin-crud-starter/internal/router"
+	"github.com/ladderseeker/gin-crud-starter/internal/softlimit"
+	"github.com/ladderseeker/gin-crud-starter/internal/worker"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+	"github.com/ladderseeker/gin-crud-starter/pkg/version"
 	"net/http"
 	"os"
 	"os/signal"
@@ -18,9 +25,10 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	router *gin.Engine
-	config *config.Config
-	db     *gorm.DB
+	router  *gin.Engine
+	config  *config.Config
+	db      *gorm.DB
+	workers *worker.Group
 }
 
 // NewServer creates a new server instance
@@ -28,34 +36,144 @@ func NewServer(config *config.Config, db *gorm.DB) *Server {
 	// Set Gin mode
 	gin.SetMode(config.Server.Mode)
 
+	// Set response key convention
+	response.SetKeyCase(config.Server.JSONCase)
+
+	// Set response timestamp zone
+	model.SetTimestampZone(config.Server.ResponseTimezone)
+
 	// Create rt
 	rt := gin.New()
 
 	return &Server{
-		router: rt,
-		config: config,
-		db:     db,
+		router:  rt,
+		config:  config,
+		db:      db,
+		workers: worker.NewGroup(context.Background()),
+	}
+}
+
+// Workers returns the group background workers are registered with, they are drained on shutdown
+func (s *Server) Workers() *worker.Group {
+	return s.workers
+}
+
+// newHTTPServer creates the HTTP server, applying the TLS policy when TLS is enabled
+func newHTTPServer(handler http.Handler, conf *config.ServerConfig) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:         ":" + conf.Port,
+		Handler:      handler,
+		ReadTimeout:  conf.ReadTimeout,
+		WriteTimeout: conf.WriteTimeout,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	if conf.TLS.Enabled() {
+		tlsConfig, err := conf.TLS.Build()
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
+	}
+	return srv, nil
+}
+
+// newDebugServer creates the diagnostics server listening on DebugAddr.
+// It has no write timeout so CPU profiles and traces can run for as long as requested.
+func newDebugServer(handler http.Handler, conf *config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              conf.DebugAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: conf.ReadTimeout,
+		IdleTimeout:       120 * time.Second,
 	}
 }
 
 // Start starts the server
 func (s *Server) Start() error {
+	// Log request latency percentiles periodically
+	if interval := s.config.Logging.LatencySummary; interval > 0 {
+		latency := middleware.NewLatencyAggregator(middleware.DefaultLatencySampleSize)
+		s.router.Use(middleware.RecordLatency(latency))
+		s.workers.Go("latency-summary", func(ctx context.Context) error {
+			return latency.Run(ctx, interval)
+		})
+	}
+
+	// Run async jobs when a worker pool is configured, draining them on shutdown
+	var queue *jobs.Queue
+	if s.config.Jobs.Workers > 0 {
+		queue = jobs.NewQueue(s.config.Jobs.Workers, s.config.Jobs.QueueSize)
+		jobs.SetDefault(queue)
+		s.workers.Go("jobs", queue.Run)
+	}
+
+	// Warn as the connection pool and job queue near their limits
+	if interval := s.config.SoftLimit.Interval; interval > 0 {
+		sqlDB, err := s.db.DB()
+		if err != nil {
+			return err
+		}
+		monitor := softlimit.NewMonitor(float64(s.config.SoftLimit.Percent) / 100)
+		monitor.Add("database_connections", func() (int, int) {
+			stats := sqlDB.Stats()
+			return stats.InUse, stats.MaxOpenConnections
+		})
+		if queue != nil {
+			monitor.Add("job_queue", queue.Usage)
+		}
+		s.workers.Go("soft-limits", func(ctx context.Context) error {
+			return monitor.Run(ctx, interval)
+		})
+	}
+
 	// Setup router
-	router.SetupRoutes(s.router, s.db)
+	if err := router.SetupRoutes(s.router, s.db, s.config); err != nil {
+		return err
+	}
 
 	// Create HTTP server
-	srv := &http.Server{
-		Addr:         ":" + s.config.Server.Port,
-		Handler:      s.router,
-		ReadTimeout:  s.config.Server.ReadTimeout,
-		WriteTimeout: s.config.Server.WriteTimeout,
-		IdleTimeout:  120 * time.Second,
+	srv, err := newHTTPServer(s.router, &s.config.Server)
+	if err != nil {
+		return err
+	}
+
+	// Serve runtime diagnostics on their own listener, away from the public API
+	var debugSrv *http.Server
+	if s.config.Server.DebugEndpoints {
+		debugRouter := gin.New()
+		if err := router.SetupDebugRoutes(debugRouter, s.db, s.config); err != nil {
+			return err
+		}
+		debugSrv = newDebugServer(debugRouter, &s.config.Server)
+		go func() {
+			logger.Warn("Debug endpoints enabled, keep DEBUG_ADDR unreachable from untrusted networks",
+				zap.String("addr", debugSrv.Addr),
+				zap.Bool("pprof", s.config.Server.DebugPprof))
+			if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Debug server stopped", zap.Error(err))
+			}
+		}()
 	}
 
 	// Start the server in a goroutine
 	go func() {
-		logger.Info("Server starting", zap.String("port", s.config.Server.Port))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		tlsConf := s.config.Server.TLS
+		build := version.Get()
+		logger.Info("Server starting",
+			zap.String("port", s.config.Server.Port),
+			zap.Bool("tls", tlsConf.Enabled()),
+			zap.String("version", build.Version),
+			zap.String("commit", build.Commit),
+			zap.String("build_date", build.BuildDate))
+
+		var err error
+		if tlsConf.Enabled() {
+			err = srv.ListenAndServeTLS(tlsConf.CertFile, tlsConf.KeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Error starting server", zap.Error(err))
 		}
 	}()
@@ -75,6 +193,17 @@ func (s *Server) Start() error {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 		return err
 	}
+	if debugSrv != nil {
+		if err := debugSrv.Shutdown(ctx); err != nil {
+			logger.Error("Debug server forced to shutdown", zap.Error(err))
+		}
+	}
+
+	// Drain background workers within the same deadline
+	if err := s.workers.Shutdown(ctx); err != nil {
+		logger.Error("Background workers did not stop cleanly", zap.Error(err))
+		return err
+	}
 
 	logger.Info("Server exited gracefully")
 	return nil
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPServerTLS(t *testing.T) {
+	testCases := []struct {
+		name            string
+		tls             config.TLSConfig
+		expectedVersion uint16
+		hardened        bool
+		expectErr       bool
+	}{
+		{
+			name:            "TLS12",
+			tls:             config.TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", MinVersion: "1.2"},
+			expectedVersion: tls.VersionTLS12,
+		},
+		{
+			name:            "TLS13Hardened",
+			tls:             config.TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", MinVersion: "1.3", HardenedCiphers: true},
+			expectedVersion: tls.VersionTLS13,
+			hardened:        true,
+		},
+		{
+			name:      "BelowTLS12",
+			tls:       config.TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", MinVersion: "1.1"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, err := newHTTPServer(http.NewServeMux(), &config.ServerConfig{Port: "8443", TLS: tc.tls})
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, srv)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, srv.TLSConfig.MinVersion)
+			if tc.hardened {
+				assert.NotEmpty(t, srv.TLSConfig.CipherSuites)
+			} else {
+				assert.Empty(t, srv.TLSConfig.CipherSuites)
+			}
+		})
+	}
+}
+
+func TestNewHTTPServerWithoutTLS(t *testing.T) {
+	srv, err := newHTTPServer(http.NewServeMux(), &config.ServerConfig{Port: "8080"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, srv.TLSConfig)
+}
+
+func TestNewDebugServer(t *testing.T) {
+	srv := newDebugServer(http.NewServeMux(), &config.ServerConfig{Port: "8080", DebugAddr: "127.0.0.1:6060", WriteTimeout: 10 * time.Second})
+
+	// Assert it listens apart from the API port, without cutting long profiles short
+	assert.Equal(t, "127.0.0.1:6060", srv.Addr)
+	assert.Zero(t, srv.WriteTimeout)
+}
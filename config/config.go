@@ -1,33 +1,142 @@
 package config
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/joho/godotenv"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logging  LoggingConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Logging   LoggingConfig
+	RateLimit RateLimitConfig
+	Email     EmailConfig
+	Users     UsersConfig
+	Jobs      JobsConfig
+	SoftLimit SoftLimitConfig
 }
 
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	Mode         string
+	Port             string
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	Mode             string
+	JSONCase         string
+	RequestIDHeader  string
+	JSONMaxDepth     int
+	JSONMaxKeys      int
+	StrictJSON       bool
+	ResponseTimeout  time.Duration
+	ReadinessTTL     time.Duration
+	ReadinessWrite   bool
+	MaxURLLength     int
+	HTTPSRedirect    bool
+	MaxResponseItems int
+
+	// AdminToken is the bearer token admin-only routes require, empty disables them
+	AdminToken string
+
+	// DebugEndpoints serves runtime diagnostics on a separate listener at DebugAddr, and DebugPprof adds pprof profiles to them
+	DebugEndpoints bool
+	DebugAddr      string
+	DebugPprof     bool
+
+	// ResponseTimezone is the zone response timestamps are serialized in, loaded from the RESPONSE_TIMEZONE IANA name
+	ResponseTimezone *time.Location
+
+	TLS             TLSConfig
+	SecurityHeaders SecurityHeadersConfig
+}
+
+// SecurityHeadersConfig controls the browser security headers set on every response, empty values omit a header
+type SecurityHeadersConfig struct {
+	Enabled        bool
+	NoSniff        bool
+	FrameOptions   string
+	ReferrerPolicy string
+
+	// HSTSMaxAge is only sent when the server terminates TLS itself, zero disables it
+	HSTSMaxAge time.Duration
+}
+
+// TLSConfig enables serving HTTPS directly when both files are set
+type TLSConfig struct {
+	CertFile        string
+	KeyFile         string
+	MinVersion      string
+	HardenedCiphers bool
+}
+
+// Enabled reports whether the server terminates TLS itself
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// tlsVersions maps the accepted TLS_MIN_VERSION values to their protocol versions
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// hardenedCipherSuites are the TLS 1.2 suites offering forward secrecy and AEAD, TLS 1.3 suites are not configurable
+var hardenedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// Build returns the tls.Config for the server, rejecting versions below TLS 1.2
+func (c TLSConfig) Build() (*tls.Config, error) {
+	minVersion, ok := tlsVersions[c.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("invalid TLS_MIN_VERSION %q: must be 1.2 or 1.3", c.MinVersion)
+	}
+
+	conf := &tls.Config{MinVersion: minVersion}
+	if c.HardenedCiphers {
+		conf.CipherSuites = hardenedCipherSuites
+	}
+	return conf, nil
+}
+
+// HandlerTimeout returns the deadline for producing a response, zero unless ResponseTimeout is set.
+// It is capped to fire shortly before WriteTimeout so clients receive an error body instead of a dropped connection.
+func (s ServerConfig) HandlerTimeout() time.Duration {
+	if s.ResponseTimeout <= 0 || s.WriteTimeout <= 0 {
+		return max(s.ResponseTimeout, 0)
+	}
+
+	// Leave a margin to write the timeout response
+	margin := min(s.WriteTimeout/10, time.Second)
+	return min(s.ResponseTimeout, s.WriteTimeout-margin)
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host       string
+	Port       string
+	User       string
+	Password   string
+	DBName     string
+	SSLMode    string
+	ReplicaDSN string
+
+	// ReadRetries transient connection failures of a read query are retried, with a linearly growing ReadRetryBackoff
+	ReadRetries      int
+	ReadRetryBackoff time.Duration
+
+	// BreakerThreshold consecutive failures open the circuit breaker for BreakerCooldown, zero disables it
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
 }
 
 func (c *DatabaseConfig) GetDSN() string {
@@ -36,36 +145,206 @@ func (c *DatabaseConfig) GetDSN() string {
 }
 
 type LoggingConfig struct {
-	Level string
+	Level                string
+	SlowRequestThreshold time.Duration
+	LogSQL               bool
+	LatencySummary       time.Duration
+	MaxBodyBytes         int
+}
+
+type RateLimitConfig struct {
+	Enabled  bool
+	Requests int
+	Window   time.Duration
+	RedisURL string
+
+	// MaxConcurrentPerUser caps each user's in-flight requests, zero (the default) disables it
+	MaxConcurrentPerUser int
+}
+
+// EmailConfig controls checks on user email addresses beyond their format
+type EmailConfig struct {
+	ValidateMX bool
+	MXTimeout  time.Duration
+}
+
+// UsersConfig controls which users may be created
+type UsersConfig struct {
+	// ReservedNames are names and emails users can't take, entries ending in "*" match as prefixes
+	ReservedNames []string
+
+	// NormalizeNames stores names NFC-composed, without invisible characters and with whitespace collapsed
+	NormalizeNames bool
+}
+
+// JobsConfig sizes the in-process queue running tasks outside the request
+type JobsConfig struct {
+	// Workers is the size of the worker pool, zero leaves the queue stopped
+	Workers   int
+	QueueSize int
+}
+
+// SoftLimitConfig controls the warnings logged as resources near their hard limits
+type SoftLimitConfig struct {
+	// Interval between usage checks, zero disables the monitor
+	Interval time.Duration
+	// Percent of each hard limit at which a warning is logged
+	Percent int
 }
 
 func LoadConfig() (*Config, error) {
 	// Load .env if exist
-	_ = godotenv.Load()
+	loadDotEnv()
+
+	// Select secret provider
+	provider, err := NewSecretProvider(getEnv("SECRET_PROVIDER", "env"))
+	if err != nil {
+		return nil, err
+	}
 
+	return LoadConfigWithProvider(provider)
+}
+
+// LoadConfigWithProvider loads configuration, resolving secret:// references with the given provider
+func LoadConfigWithProvider(provider SecretProvider) (*Config, error) {
 	config := Config{
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			ReadTimeout:  getEnvDuration("SERVER_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout: getEnvDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
-			Mode:         getEnv("GIN_MODE", "debug"),
+			Port:             getEnv("SERVER_PORT", "8080"),
+			ReadTimeout:      getEnvDuration("SERVER_READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:     getEnvDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			Mode:             getEnv("GIN_MODE", "debug"),
+			JSONCase:         getEnv("JSON_CASE", ""),
+			RequestIDHeader:  getEnv("REQUEST_ID_HEADER", "X-Request-ID"),
+			JSONMaxDepth:     getEnvInt("JSON_MAX_DEPTH", 32),
+			JSONMaxKeys:      getEnvInt("JSON_MAX_KEYS", 1000),
+			StrictJSON:       getEnvBool("STRICT_JSON_BINDING", false),
+			ResponseTimeout:  getEnvDuration("SERVER_RESPONSE_TIMEOUT", 0),
+			ReadinessTTL:     time.Duration(getEnvInt("READINESS_CACHE_MS", 2000)) * time.Millisecond,
+			ReadinessWrite:   getEnvBool("READINESS_WRITE_CHECK", false),
+			MaxURLLength:     getEnvInt("MAX_URL_LENGTH", 8192),
+			HTTPSRedirect:    getEnvBool("HTTPS_REDIRECT", false),
+			MaxResponseItems: getEnvInt("MAX_RESPONSE_ITEMS", 1000),
+			AdminToken:       getEnv("ADMIN_TOKEN", ""),
+			DebugEndpoints:   getEnvBool("DEBUG_ENDPOINTS", false),
+			DebugAddr:        getEnv("DEBUG_ADDR", "127.0.0.1:6060"),
+			DebugPprof:       getEnvBool("DEBUG_PPROF", false),
+			TLS: TLSConfig{
+				CertFile:        getEnv("TLS_CERT_FILE", ""),
+				KeyFile:         getEnv("TLS_KEY_FILE", ""),
+				MinVersion:      getEnv("TLS_MIN_VERSION", "1.2"),
+				HardenedCiphers: getEnvBool("TLS_HARDENED_CIPHERS", false),
+			},
+			SecurityHeaders: SecurityHeadersConfig{
+				Enabled:        getEnvBool("SECURITY_HEADERS_ENABLED", true),
+				NoSniff:        getEnvBool("SECURITY_HEADER_NOSNIFF", true),
+				FrameOptions:   getEnv("SECURITY_HEADER_FRAME_OPTIONS", "DENY"),
+				ReferrerPolicy: getEnv("SECURITY_HEADER_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+				HSTSMaxAge:     getEnvDuration("SECURITY_HSTS_MAX_AGE", 365*24*time.Hour),
+			},
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "gin_crud"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:       getEnv("DB_HOST", "localhost"),
+			Port:       getEnv("DB_PORT", "5432"),
+			User:       getEnv("DB_USER", "postgres"),
+			Password:   getEnv("DB_PASSWORD", "postgres"),
+			DBName:     getEnv("DB_NAME", "gin_crud"),
+			SSLMode:    getEnv("DB_SSLMODE", "disable"),
+			ReplicaDSN: getEnv("DB_REPLICA_DSN", ""),
+
+			ReadRetries:      getEnvInt("DB_READ_RETRIES", 2),
+			ReadRetryBackoff: time.Duration(getEnvInt("DB_READ_RETRY_BACKOFF_MS", 50)) * time.Millisecond,
+
+			BreakerThreshold: getEnvInt("DB_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvDuration("DB_BREAKER_COOLDOWN", 30*time.Second),
 		},
 		Logging: LoggingConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
+			Level:                getEnv("LOG_LEVEL", "info"),
+			SlowRequestThreshold: time.Duration(getEnvInt("SLOW_REQUEST_MS", 0)) * time.Millisecond,
+			LogSQL:               getEnvBool("LOG_SQL", false),
+			LatencySummary:       getEnvDuration("LATENCY_SUMMARY_INTERVAL", 0),
+			MaxBodyBytes:         getEnvInt("LOG_MAX_BODY_BYTES", 1024*10),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:  getEnvBool("RATE_LIMIT_ENABLED", false),
+			Requests: getEnvInt("RATE_LIMIT_REQUESTS", 100),
+			Window:   getEnvDuration("RATE_LIMIT_WINDOW", time.Minute),
+			RedisURL: getEnv("REDIS_URL", ""),
+
+			MaxConcurrentPerUser: getEnvInt("MAX_CONCURRENT_PER_USER", 0),
+		},
+		Users: UsersConfig{
+			ReservedNames:  getEnvList("RESERVED_USER_NAMES", nil),
+			NormalizeNames: getEnvBool("NORMALIZE_USER_NAMES", false),
+		},
+		Email: EmailConfig{
+			ValidateMX: getEnvBool("VALIDATE_EMAIL_MX", false),
+			MXTimeout:  time.Duration(getEnvInt("EMAIL_MX_TIMEOUT_MS", 2000)) * time.Millisecond,
+		},
+		Jobs: JobsConfig{
+			Workers:   getEnvInt("JOB_WORKERS", 0),
+			QueueSize: getEnvInt("JOB_QUEUE_SIZE", 100),
+		},
+		SoftLimit: SoftLimitConfig{
+			Interval: getEnvDuration("SOFT_LIMIT_INTERVAL", 30*time.Second),
+			Percent:  getEnvInt("SOFT_LIMIT_PERCENT", 80),
 		},
 	}
 
+	// Validate response key convention
+	switch config.Server.JSONCase {
+	case "", "snake", "camel":
+	default:
+		return nil, fmt.Errorf("invalid JSON_CASE %q: must be snake or camel", config.Server.JSONCase)
+	}
+
+	// Validate response time zone
+	zone, err := time.LoadLocation(getEnv("RESPONSE_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RESPONSE_TIMEZONE: %w", err)
+	}
+	config.Server.ResponseTimezone = zone
+
+	// Validate TLS policy
+	if _, err := config.Server.TLS.Build(); err != nil {
+		return nil, err
+	}
+
+	// Resolve secret references
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := resolveSecrets(ctx, provider,
+		&config.Database.User,
+		&config.Database.Password,
+		&config.Database.ReplicaDSN,
+		&config.RateLimit.RedisURL,
+		&config.Server.AdminToken,
+	); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// dotEnvOnce makes .env load once per process, however many entry points read configuration
+var dotEnvOnce sync.Once
+
+// loadDotEnv loads .env into the environment if it exists, variables already set take precedence
+func loadDotEnv() {
+	dotEnvOnce.Do(func() {
+		_ = godotenv.Load()
+	})
+}
+
+// StartupTimeout bounds the whole bootstrap, from loading configuration to migrating the database.
+// It is read before the rest of the configuration so it can cover loading it.
+func StartupTimeout() time.Duration {
+	// Load .env if exist
+	loadDotEnv()
+
+	return getEnvDuration("STARTUP_TIMEOUT", 60*time.Second)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -73,6 +352,15 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if intVal, err := strconv.Atoi(value); err == nil {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
 		if intVal, err := strconv.Atoi(value); err == nil {
@@ -82,6 +370,19 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvList(key string, defaultValue []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSecretProvider resolves secrets from an in-memory map
+type fakeSecretProvider struct {
+	secrets map[string]string
+}
+
+func (p *fakeSecretProvider) GetSecret(_ context.Context, name string) (string, error) {
+	if value, ok := p.secrets[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret %q not found", name)
+}
+
+func TestLoadConfigResolvesSecrets(t *testing.T) {
+	// Reference a secret for the password and keep the user literal
+	t.Setenv("DB_USER", "app")
+	t.Setenv("DB_PASSWORD", "secret://projects/demo/secrets/db-password")
+
+	provider := &fakeSecretProvider{secrets: map[string]string{
+		"projects/demo/secrets/db-password": "s3cr3t",
+	}}
+
+	// Load configuration
+	conf, err := LoadConfigWithProvider(provider)
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.Equal(t, "app", conf.Database.User)
+	assert.Equal(t, "s3cr3t", conf.Database.Password)
+}
+
+func TestLoadConfigUnresolvableSecret(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "secret://missing")
+
+	conf, err := LoadConfigWithProvider(&fakeSecretProvider{})
+
+	assert.Error(t, err)
+	assert.Nil(t, conf)
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "from-env")
+	t.Setenv("DB_PASSWORD", "secret://APP_DB_PASSWORD")
+
+	conf, err := LoadConfigWithProvider(&EnvSecretProvider{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", conf.Database.Password)
+}
+
+// fakeHTTPDoer answers requests by URL from canned responses and records them
+type fakeHTTPDoer struct {
+	responses map[string]string
+	requests  []*http.Request
+}
+
+func (d *fakeHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+	body, ok := d.responses[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestGCPSecretProvider(t *testing.T) {
+	testCases := []struct {
+		name        string
+		reference   string
+		expectedURL string
+		expectErr   bool
+	}{
+		{name: "LatestVersion", reference: "projects/demo/secrets/db-password", expectedURL: "https://secrets.test/v1/projects/demo/secrets/db-password/versions/latest:access"},
+		{name: "PinnedVersion", reference: "projects/demo/secrets/db-password/versions/3", expectedURL: "https://secrets.test/v1/projects/demo/secrets/db-password/versions/3:access"},
+		{name: "MissingSecret", reference: "projects/demo/secrets/missing", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Serve a token from the metadata server and the secret from Secret Manager
+			doer := &fakeHTTPDoer{responses: map[string]string{
+				"http://metadata.test/token": `{"access_token":"tok3n"}`,
+				tc.expectedURL:               `{"payload":{"data":"` + base64.StdEncoding.EncodeToString([]byte("s3cr3t")) + `"}}`,
+			}}
+			provider := &GCPSecretProvider{client: doer, apiURL: "https://secrets.test/v1", metadataURL: "http://metadata.test/token"}
+
+			// Resolve the reference
+			secret, err := provider.GetSecret(context.Background(), tc.reference)
+
+			// Assert results
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Empty(t, secret)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "s3cr3t", secret)
+			assert.Len(t, doer.requests, 2)
+			assert.Equal(t, "Google", doer.requests[0].Header.Get("Metadata-Flavor"))
+			assert.Equal(t, "Bearer tok3n", doer.requests[1].Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		server   ServerConfig
+		expected time.Duration
+	}{
+		{name: "Disabled", server: ServerConfig{}, expected: 0},
+		{name: "OffByDefault", server: ServerConfig{WriteTimeout: 10 * time.Second}, expected: 0},
+		{name: "ShortWriteTimeout", server: ServerConfig{WriteTimeout: 2 * time.Second, ResponseTimeout: 5 * time.Second}, expected: 1800 * time.Millisecond},
+		{name: "ConfiguredBelowLimit", server: ServerConfig{WriteTimeout: 10 * time.Second, ResponseTimeout: 5 * time.Second}, expected: 5 * time.Second},
+		{name: "ConfiguredAboveLimit", server: ServerConfig{WriteTimeout: 10 * time.Second, ResponseTimeout: 30 * time.Second}, expected: 9 * time.Second},
+		{name: "NoWriteTimeout", server: ServerConfig{ResponseTimeout: 5 * time.Second}, expected: 5 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.server.HandlerTimeout())
+		})
+	}
+}
+
+func TestLoadConfigRejectsWeakTLSVersion(t *testing.T) {
+	t.Setenv("TLS_MIN_VERSION", "1.0")
+
+	conf, err := LoadConfigWithProvider(&fakeSecretProvider{})
+
+	assert.ErrorContains(t, err, "TLS_MIN_VERSION")
+	assert.Nil(t, conf)
+}
+
+func TestLoadConfigConcurrencyLimit(t *testing.T) {
+	// Off by default
+	conf, err := LoadConfigWithProvider(&fakeSecretProvider{})
+	assert.NoError(t, err)
+	assert.Zero(t, conf.RateLimit.MaxConcurrentPerUser)
+
+	// Enabled by setting a limit
+	t.Setenv("MAX_CONCURRENT_PER_USER", "4")
+	conf, err = LoadConfigWithProvider(&fakeSecretProvider{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, conf.RateLimit.MaxConcurrentPerUser)
+}
+
+func TestLoadConfigResponseTimezone(t *testing.T) {
+	// Defaults to UTC
+	conf, err := LoadConfigWithProvider(&fakeSecretProvider{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, conf.Server.ResponseTimezone)
+
+	// Loads a valid IANA zone
+	t.Setenv("RESPONSE_TIMEZONE", "Asia/Tokyo")
+	conf, err = LoadConfigWithProvider(&fakeSecretProvider{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", conf.Server.ResponseTimezone.String())
+
+	// Rejects an unknown zone
+	t.Setenv("RESPONSE_TIMEZONE", "Mars/Olympus_Mons")
+	conf, err = LoadConfigWithProvider(&fakeSecretProvider{})
+	assert.ErrorContains(t, err, "RESPONSE_TIMEZONE")
+	assert.Nil(t, conf)
+}
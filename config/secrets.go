@@ -0,0 +1,150 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SecretPrefix marks a config value as a reference to be resolved by a SecretProvider
+const SecretPrefix = "secret://"
+
+// SecretProvider resolves secret references to their values
+type SecretProvider interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+}
+
+// NewSecretProvider creates a secret provider by name
+func NewSecretProvider(name string) (SecretProvider, error) {
+	switch strings.ToLower(name) {
+	case "", "env":
+		return &EnvSecretProvider{}, nil
+	case "gcp":
+		return NewGCPSecretProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown secret provider %q", name)
+	}
+}
+
+// EnvSecretProvider resolves secret references from environment variables,
+// so secret://APP_DB_PASSWORD reads the APP_DB_PASSWORD variable
+type EnvSecretProvider struct{}
+
+// GetSecret returns the value of the environment variable with the given name
+func (p *EnvSecretProvider) GetSecret(_ context.Context, name string) (string, error) {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+// httpDoer sends HTTP requests, *http.Client implements it
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// GCPSecretProvider resolves secret references from Google Cloud Secret Manager.
+// References are resource names such as projects/my-project/secrets/db-password,
+// optionally followed by /versions/<version> (defaults to latest).
+// Access tokens are obtained from the GCE metadata server.
+type GCPSecretProvider struct {
+	client      httpDoer
+	apiURL      string
+	metadataURL string
+}
+
+// NewGCPSecretProvider creates a new Google Cloud Secret Manager provider
+func NewGCPSecretProvider() *GCPSecretProvider {
+	return &GCPSecretProvider{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		apiURL:      "https://secretmanager.googleapis.com/v1",
+		metadataURL: "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token",
+	}
+}
+
+// GetSecret accesses the secret version and returns its payload
+func (p *GCPSecretProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := p.getJSON(req, &body); err != nil {
+		return "", fmt.Errorf("failed to access secret %q: %w", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %q: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// accessToken fetches an OAuth2 access token from the metadata server
+func (p *GCPSecretProvider) accessToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.getJSON(req, &body); err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	return body.AccessToken, nil
+}
+
+func (p *GCPSecretProvider) getJSON(req *http.Request, out any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// resolveSecrets replaces secret:// references with values from the provider.
+// Values without the prefix are kept as literals.
+func resolveSecrets(ctx context.Context, provider SecretProvider, values ...*string) error {
+	for _, value := range values {
+		if !strings.HasPrefix(*value, SecretPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(*value, SecretPrefix)
+		secret, err := provider.GetSecret(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret %q: %w", name, err)
+		}
+		*value = secret
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/router/registry"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+)
+
+// DBStatser reports connection pool statistics, *sql.DB implements it
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+// AdminController serves operator diagnostics. Routes must only be registered on the debug listener, never the public API.
+type AdminController struct {
+	db    DBStatser
+	pprof bool
+}
+
+// NewAdminController creates an admin controller reporting db's pool, exposing pprof profiles when enablePprof is set
+func NewAdminController(db DBStatser, enablePprof bool) *AdminController {
+	return &AdminController{
+		db:    db,
+		pprof: enablePprof,
+	}
+}
+
+// Register registers the router for the admin controller
+func (c *AdminController) Register(router *registry.Group) {
+	admin := router.Group("/admin")
+	{
+		admin.GET("/debug", c.GetDiagnostics)
+		if c.pprof {
+			admin.GET("/debug/pprof/", gin.WrapF(pprof.Index))
+			admin.GET("/debug/pprof/:profile", profileHandler)
+		}
+	}
+}
+
+// GetDiagnostics returns a snapshot of the server runtime
+// @Summary Get runtime diagnostics
+// @Description Goroutine count, memory and GC statistics, and database pool usage
+// @Tags admin
+// @Produce json
+// @Success 200 {object} model.Diagnostics
+// @Router /admin/debug [get]
+func (c *AdminController) GetDiagnostics(ctx *gin.Context) {
+	response.Send(ctx, http.StatusOK, c.diagnostics())
+}
+
+// diagnostics collects the runtime snapshot
+func (c *AdminController) diagnostics() model.Diagnostics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+
+	diagnostics := model.Diagnostics{
+		GoVersion:  runtime.Version(),
+		NumCPU:     runtime.NumCPU(),
+		Goroutines: runtime.NumGoroutine(),
+		Memory: model.MemoryDiagnostics{
+			AllocBytes:      mem.Alloc,
+			TotalAllocBytes: mem.TotalAlloc,
+			SysBytes:        mem.Sys,
+			HeapAllocBytes:  mem.HeapAlloc,
+			HeapInuseBytes:  mem.HeapInuse,
+			HeapObjects:     mem.HeapObjects,
+			Mallocs:         mem.Mallocs,
+			Frees:           mem.Frees,
+		},
+		GC: model.GCDiagnostics{
+			NumGC:        gc.NumGC,
+			PauseTotalMs: float64(gc.PauseTotal.Microseconds()) / 1000,
+			NextGCBytes:  mem.NextGC,
+		},
+	}
+	if !gc.LastGC.IsZero() {
+		lastGC := model.NewTimestamp(gc.LastGC)
+		diagnostics.GC.LastGC = &lastGC
+	}
+
+	if c.db != nil {
+		stats := c.db.Stats()
+		diagnostics.Database = &model.DatabaseDiagnostics{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     float64(stats.WaitDuration.Microseconds()) / 1000,
+		}
+	}
+	return diagnostics
+}
+
+// profileHandler serves a named pprof profile, net/http/pprof only resolves names under /debug/pprof/.
+// The command line is left out since it may carry secrets.
+func profileHandler(ctx *gin.Context) {
+	switch name := ctx.Param("profile"); name {
+	case "cmdline":
+		ctx.Status(http.StatusNotFound)
+	case "profile":
+		pprof.Profile(ctx.Writer, ctx.Request)
+	case "symbol":
+		pprof.Symbol(ctx.Writer, ctx.Request)
+	case "trace":
+		pprof.Trace(ctx.Writer, ctx.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
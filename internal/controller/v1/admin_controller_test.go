@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/router/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDBStats reports fixed pool statistics
+type fakeDBStats sql.DBStats
+
+func (f fakeDBStats) Stats() sql.DBStats {
+	return sql.DBStats(f)
+}
+
+func newAdminTestRouter(enablePprof bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	db := fakeDBStats{MaxOpenConnections: 25, OpenConnections: 3, InUse: 1, Idle: 2}
+	NewAdminController(db, enablePprof).Register(registry.New().Wrap(router.Group("/api/v1")))
+	return router
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	router := newAdminTestRouter(false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/debug", nil))
+
+	// Assert the runtime fields are reported
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body, "go_version")
+	assert.Greater(t, body["goroutines"], float64(0))
+	assert.Greater(t, body["num_cpu"], float64(0))
+
+	memory := body["memory"].(map[string]any)
+	for _, field := range []string{"alloc_bytes", "sys_bytes", "heap_alloc_bytes", "heap_objects"} {
+		assert.Contains(t, memory, field)
+	}
+	assert.Contains(t, body["gc"], "num_gc")
+	assert.Equal(t, map[string]any{
+		"max_open_connections": float64(25),
+		"open_connections":     float64(3),
+		"in_use":               float64(1),
+		"idle":                 float64(2),
+		"wait_count":           float64(0),
+		"wait_duration_ms":     float64(0),
+	}, body["database"])
+}
+
+func TestDiagnosticsPprof(t *testing.T) {
+	testCases := []struct {
+		name           string
+		enablePprof    bool
+		path           string
+		expectedStatus int
+	}{
+		{name: "Disabled", enablePprof: false, path: "/api/v1/admin/debug/pprof/goroutine", expectedStatus: http.StatusNotFound},
+		{name: "NamedProfile", enablePprof: true, path: "/api/v1/admin/debug/pprof/goroutine?debug=1", expectedStatus: http.StatusOK},
+		{name: "CommandLineHidden", enablePprof: true, path: "/api/v1/admin/debug/pprof/cmdline", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newAdminTestRouter(tc.enablePprof)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
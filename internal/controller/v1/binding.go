@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Default limits on the structure of inbound JSON bodies
+const (
+	DefaultJSONMaxDepth = 32
+	DefaultJSONMaxKeys  = 1000
+)
+
+// jsonMaxDepth and jsonMaxKeys bound inbound JSON bodies, zero disables the check
+var (
+	jsonMaxDepth = DefaultJSONMaxDepth
+	jsonMaxKeys  = DefaultJSONMaxKeys
+)
+
+// strictJSON rejects unknown fields in every request body when set
+var strictJSON bool
+
+// strictJSONKey marks a request whose route requires strict binding
+const strictJSONKey = "strict_json"
+
+// unknownFieldPrefix starts the decoder error for a field the target struct doesn't declare
+const unknownFieldPrefix = "json: unknown field "
+
+// SetStrictJSON makes every endpoint reject request bodies with unknown fields
+func SetStrictJSON(enabled bool) {
+	strictJSON = enabled
+}
+
+// StrictJSON makes the routes it is applied to reject request bodies with unknown fields
+func StrictJSON() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(strictJSONKey, true)
+		ctx.Next()
+	}
+}
+
+// SetJSONLimits sets the maximum nesting depth and total object key count accepted in request bodies
+func SetJSONLimits(maxDepth, maxKeys int) {
+	jsonMaxDepth = maxDepth
+	jsonMaxKeys = maxKeys
+}
+
+// bindJSON checks the structure of the request body and binds it into obj.
+// Any failure is returned as an INVALID_INPUT error wrapping the cause.
+func bindJSON(ctx *gin.Context, obj any) error {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return apperrors.NewInvalidInputError("Failed to read request body", nil, err)
+	}
+
+	if err := checkJSONLimits(body, jsonMaxDepth, jsonMaxKeys); err != nil {
+		return apperrors.NewInvalidInputError(err.Error(), nil, err)
+	}
+
+	if err := decodeJSON(body, obj, strictJSON || ctx.GetBool(strictJSONKey)); err != nil {
+		return apperrors.NewInvalidInputError(decodeErrorMessage(err), nil, err)
+	}
+
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return apperrors.NewInvalidInputError("Invalid input", nil, err)
+	}
+	return nil
+}
+
+// decodeJSON decodes body into obj, failing on fields obj doesn't declare when strict is set
+func decodeJSON(body []byte, obj any, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
+
+// decodeErrorMessage describes JSON decoding failures with their byte offset and expected type
+func decodeErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case stderrors.As(err, &syntaxErr):
+		return fmt.Sprintf("Malformed JSON at byte offset %d: %s", syntaxErr.Offset, syntaxErr.Error())
+	case stderrors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return fmt.Sprintf("Invalid value for field %q at byte offset %d: expected %s but got %s",
+				typeErr.Field, typeErr.Offset, typeErr.Type, typeErr.Value)
+		}
+		return fmt.Sprintf("Invalid value at byte offset %d: expected %s but got %s", typeErr.Offset, typeErr.Type, typeErr.Value)
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		return fmt.Sprintf("Unknown field %s", strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+	case stderrors.Is(err, io.EOF):
+		return "Request body is empty"
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		return "Malformed JSON: unexpected end of input"
+	default:
+		return "Invalid input"
+	}
+}
+
+// jsonFrame tracks the container being scanned by checkJSONLimits
+type jsonFrame struct {
+	object    bool
+	expectKey bool
+}
+
+// checkJSONLimits scans body and fails if it nests deeper than maxDepth or has more than maxKeys object keys.
+// Malformed JSON is left for the decoder to report.
+func checkJSONLimits(body []byte, maxDepth, maxKeys int) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	var stack []jsonFrame
+	keys := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		// Object keys alternate with values
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.object {
+				if top.expectKey {
+					if _, isDelim := token.(json.Delim); !isDelim {
+						keys++
+						if maxKeys > 0 && keys > maxKeys {
+							return fmt.Errorf("Request body exceeds the maximum of %d keys", maxKeys)
+						}
+						top.expectKey = false
+						continue
+					}
+				} else {
+					top.expectKey = true
+				}
+			}
+		}
+
+		delim, ok := token.(json.Delim)
+		if !ok {
+			continue
+		}
+		switch delim {
+		case '{', '[':
+			stack = append(stack, jsonFrame{object: delim == '{', expectKey: true})
+			if maxDepth > 0 && len(stack) > maxDepth {
+				return fmt.Errorf("Request body exceeds the maximum nesting depth of %d", maxDepth)
+			}
+		case '}', ']':
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// redactedValue replaces the values of sensitive fields in logs
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldMarkers identifies fields whose values must never be logged
+var sensitiveFieldMarkers = []string{"password", "secret", "token"}
+
+// validationFailure describes one failed validation rule for logging
+type validationFailure struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	Param string `json:"param,omitempty"`
+	Value any    `json:"value"`
+}
+
+// validationErrorField builds a structured log field from a binding error.
+// Validation failures list each failed field with sensitive values redacted;
+// any other error is logged as is.
+func validationErrorField(err error) zap.Field {
+	var validationErrs validator.ValidationErrors
+	if !stderrors.As(err, &validationErrs) {
+		return zap.Error(err)
+	}
+
+	failures := make([]validationFailure, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		value := fieldErr.Value()
+		if isSensitiveField(fieldErr.Field()) {
+			value = redactedValue
+		}
+		failures = append(failures, validationFailure{
+			Field: fieldErr.Namespace(),
+			Tag:   fieldErr.Tag(),
+			Param: fieldErr.Param(),
+			Value: value,
+		})
+	}
+	return zap.Any("validation_errors", failures)
+}
+
+// isSensitiveField checks if a field name looks like it holds a credential
+func isSensitiveField(field string) bool {
+	lower := strings.ToLower(field)
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
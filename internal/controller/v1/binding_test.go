@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs replaces the global logger with one that records entries
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
+	return logs
+}
+
+func TestValidationFailureLogging(t *testing.T) {
+	logs := observeLogs(t)
+	router := newTestRouter(new(MockUserService))
+
+	// Submit an invalid email and a too-short password
+	body := `{"name":"John","email":"not-an-email","password":"abc12"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Assert the log names the failing fields without leaking the password
+	entries := logs.FilterMessage("Invalid input for creating user").All()
+	assert.Len(t, entries, 1)
+
+	logged := fmt.Sprintf("%+v", entries[0].ContextMap()["validation_errors"])
+	assert.Contains(t, logged, "UserCreate.Email")
+	assert.Contains(t, logged, "not-an-email")
+	assert.Contains(t, logged, "UserCreate.Password")
+	assert.Contains(t, logged, redactedValue)
+	assert.NotContains(t, logged, "abc12")
+}
+
+func TestBindJSONLimits(t *testing.T) {
+	SetJSONLimits(4, 5)
+	t.Cleanup(func() { SetJSONLimits(DefaultJSONMaxDepth, DefaultJSONMaxKeys) })
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedMsg  string
+	}{
+		{
+			name:         "DeeplyNested",
+			body:         `{"name":"John","role":[[[[1]]]]}`,
+			expectedCode: http.StatusBadRequest,
+			expectedMsg:  "maximum nesting depth of 4",
+		},
+		{
+			name:         "TooManyKeys",
+			body:         `{"name":"John","email":"john@example.com","password":"password123","a":1,"b":2,"c":3}`,
+			expectedCode: http.StatusBadRequest,
+			expectedMsg:  "maximum of 5 keys",
+		},
+		{
+			name:         "WithinLimits",
+			body:         `{"name":"John","email":"john@example.com","password":"password123"}`,
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("CreateUser", mock.Anything, mock.Anything).
+				Return(&model.UserResponse{ID: 1, Name: "John", Email: "john@example.com"}, nil).Maybe()
+			router := newTestRouter(mockService)
+
+			// Submit the body
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			// Assert the response
+			assert.Equal(t, tc.expectedCode, w.Code)
+			if tc.expectedMsg != "" {
+				assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+				assert.Contains(t, w.Body.String(), tc.expectedMsg)
+				mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBindJSONDecodeErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		expectedMsg string
+	}{
+		{
+			name:        "TypeMismatch",
+			body:        `{"user_ids":"1","active":true}`,
+			expectedMsg: `Invalid value for field "user_ids" at byte offset 15: expected []uint but got string`,
+		},
+		{
+			name:        "SyntaxError",
+			body:        `{"user_ids":[1,2],"active":tru}`,
+			expectedMsg: "Malformed JSON at byte offset 31: invalid character '}' in literal true (expecting 'e')",
+		},
+		{
+			name:        "Truncated",
+			body:        `{"user_ids":[1,2]`,
+			expectedMsg: "Malformed JSON: unexpected end of input",
+		},
+		{
+			name:        "Empty",
+			body:        ``,
+			expectedMsg: "Request body is empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newTestRouter(new(MockUserService))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users/bulk-status", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			router.ServeHTTP(w, req)
+
+			var body map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "INVALID_INPUT", body["code"])
+			assert.Equal(t, tc.expectedMsg, body["message"])
+		})
+	}
+}
+
+func TestBindJSONStrict(t *testing.T) {
+	testCases := []struct {
+		name           string
+		strict         bool
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "StrictOff", method: http.MethodPost, path: "/api/v1/users", body: `{"name":"John","email":"john@example.com","password":"secret1","nmae":"John"}`, expectedStatus: http.StatusCreated},
+		{name: "StrictGlobal", strict: true, method: http.MethodPost, path: "/api/v1/users", body: `{"name":"John","email":"john@example.com","password":"secret1","nmae":"John"}`, expectedStatus: http.StatusBadRequest},
+		{name: "StrictUpdateRoute", method: http.MethodPut, path: "/api/v1/users/1", body: `{"nmae":"John"}`, expectedStatus: http.StatusBadRequest},
+		{name: "StrictRoleRoute", method: http.MethodPut, path: "/api/v1/users/1/role", body: `{"role":"admin","nmae":"John"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetStrictJSON(tc.strict)
+			t.Cleanup(func() { SetStrictJSON(false) })
+
+			mockService := new(MockUserService)
+			mockService.On("CreateUser", mock.Anything, mock.Anything).
+				Return(&model.UserResponse{ID: 1, Name: "John"}, nil).Maybe()
+			router := newTestRouter(mockService)
+
+			// Submit a body with a misspelled field through the registered route as an admin
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			router.ServeHTTP(w, req)
+
+			// Assert the unknown field is rejected or ignored
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusBadRequest {
+				var body map[string]any
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "INVALID_INPUT", body["code"])
+				assert.Equal(t, `Unknown field "nmae"`, body["message"])
+				assert.Empty(t, mockService.Calls)
+			}
+		})
+	}
+}
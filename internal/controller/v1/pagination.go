@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+)
+
+// parsePage reads pagination parameters in either style and translates them into the same bounds.
+// offset/limit take precedence over page/page_size when both are given.
+// Without any parameters the page is unbounded.
+func parsePage(ctx *gin.Context) (model.Page, error) {
+	_, hasOffset := ctx.GetQuery("offset")
+	_, hasLimit := ctx.GetQuery("limit")
+	if hasOffset || hasLimit {
+		offset, err := queryInt(ctx, "offset", 0, 0)
+		if err != nil {
+			return model.Page{}, err
+		}
+		limit, err := queryInt(ctx, "limit", model.MaxPageSize, 1)
+		if err != nil {
+			return model.Page{}, err
+		}
+		return model.Page{Offset: offset, Limit: min(limit, model.MaxPageSize)}, nil
+	}
+
+	_, hasPage := ctx.GetQuery("page")
+	_, hasPageSize := ctx.GetQuery("page_size")
+	if hasPage || hasPageSize {
+		page, err := queryInt(ctx, "page", 1, 1)
+		if err != nil {
+			return model.Page{}, err
+		}
+		pageSize, err := queryInt(ctx, "page_size", model.MaxPageSize, 1)
+		if err != nil {
+			return model.Page{}, err
+		}
+		pageSize = min(pageSize, model.MaxPageSize)
+
+		// Reject pages whose offset would overflow
+		if page > math.MaxInt/pageSize {
+			return model.Page{}, apperrors.NewInvalidInputError(
+				fmt.Sprintf("Invalid page: must be at most %d for a page_size of %d", math.MaxInt/pageSize, pageSize),
+				map[string]interface{}{"page": page}, nil)
+		}
+		return model.Page{Offset: (page - 1) * pageSize, Limit: pageSize}, nil
+	}
+
+	return model.Page{}, nil
+}
+
+// queryInt reads an integer query parameter that must be at least minValue
+func queryInt(ctx *gin.Context, name string, defaultValue, minValue int) (int, error) {
+	raw, ok := ctx.GetQuery(name)
+	if !ok {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minValue {
+		return 0, apperrors.NewInvalidInputError(
+			fmt.Sprintf("Invalid %s: must be an integer of at least %d", name, minValue),
+			map[string]interface{}{name: raw}, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		expected     model.Page
+		expectedCode string
+	}{
+		{name: "Unbounded", query: "", expected: model.Page{}},
+		{name: "OffsetLimit", query: "offset=20&limit=10", expected: model.Page{Offset: 20, Limit: 10}},
+		{name: "PageSize", query: "page=3&page_size=10", expected: model.Page{Offset: 20, Limit: 10}},
+		{name: "LastSafePage", query: fmt.Sprintf("page=%d&page_size=10", math.MaxInt/10), expected: model.Page{Offset: (math.MaxInt/10 - 1) * 10, Limit: 10}},
+		{name: "OffsetOverflow", query: fmt.Sprintf("page=%d&page_size=10", math.MaxInt/10+1), expectedCode: apperrors.ErrCodeInvalidInput},
+		{name: "MaxPage", query: fmt.Sprintf("page=%d", math.MaxInt), expectedCode: apperrors.ErrCodeInvalidInput},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/users?"+tc.query, nil)
+
+			page, err := parsePage(ctx)
+
+			if tc.expectedCode != "" {
+				var appErr *apperrors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tc.expectedCode, appErr.Code)
+				assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, page)
+		})
+	}
+}
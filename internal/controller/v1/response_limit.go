@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxResponseItems is the most items a list response serializes when no limit is configured
+const DefaultMaxResponseItems = 1000
+
+// HeaderResultTruncated marks list responses cut short by the response item cap
+const HeaderResultTruncated = "X-Result-Truncated"
+
+// maxResponseItems caps every non-streaming list response, zero disables the cap
+var maxResponseItems = DefaultMaxResponseItems
+
+// SetMaxResponseItems sets the most items any list response serializes.
+// It is a last line of defense independent of page size clamping.
+func SetMaxResponseItems(max int) {
+	maxResponseItems = max
+}
+
+// capResponseItems truncates items to the response item cap, warning and flagging the response when it does
+func capResponseItems[T any](ctx *gin.Context, items []T) []T {
+	if maxResponseItems <= 0 || len(items) <= maxResponseItems {
+		return items
+	}
+
+	logger.FromContext(ctx.Request.Context()).Warn("Truncated list response to the maximum item count",
+		zap.String("route", ctx.FullPath()),
+		zap.Int("items", len(items)),
+		zap.Int("max", maxResponseItems))
+	ctx.Header(HeaderResultTruncated, "true")
+	return items[:maxResponseItems]
+}
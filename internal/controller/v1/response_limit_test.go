@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAllUsersResponseItemCap(t *testing.T) {
+	logs := observeLogs(t)
+	SetMaxResponseItems(2)
+	t.Cleanup(func() { SetMaxResponseItems(DefaultMaxResponseItems) })
+
+	// The service returns more users than the cap
+	mockService := new(MockUserService)
+	mockService.On("GetAllUsers", mock.Anything, model.Page{}).Return([]model.UserResponse{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+	router := newTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	// Assert the response was truncated and flagged
+	assert.Equal(t, http.StatusOK, w.Code)
+	var users []model.UserResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+	assert.Len(t, users, 2)
+	assert.Equal(t, "true", w.Header().Get(HeaderResultTruncated))
+
+	// Assert the clamp was logged
+	entries := logs.FilterMessage("Truncated list response to the maximum item count").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int64(3), entries[0].ContextMap()["items"])
+	assert.Equal(t, int64(2), entries[0].ContextMap()["max"])
+}
+
+func TestGetAllUsersWithinResponseItemCap(t *testing.T) {
+	logs := observeLogs(t)
+	SetMaxResponseItems(2)
+	t.Cleanup(func() { SetMaxResponseItems(DefaultMaxResponseItems) })
+
+	mockService := new(MockUserService)
+	mockService.On("GetAllUsers", mock.Anything, model.Page{}).Return([]model.UserResponse{{ID: 1}, {ID: 2}}, nil)
+	router := newTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	// Assert nothing was clamped
+	var users []model.UserResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+	assert.Len(t, users, 2)
+	assert.Empty(t, w.Header().Get(HeaderResultTruncated))
+	assert.Zero(t, logs.FilterMessage("Truncated list response to the maximum item count").Len())
+}
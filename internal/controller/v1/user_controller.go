@@ -2,17 +2,23 @@ package v1
 
 import (
 	stderrors "errors"
+	"github.com/ladderseeker/gin-crud-starter/internal/middleware"
 	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/router/registry"
 	"github.com/ladderseeker/gin-crud-starter/internal/service"
 	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
+	"github.com/gin-gonic/gin/binding"
 )
 
+// errInvalidID is returned for IDs that parse but can never exist
+var errInvalidID = stderrors.New("id must be a positive integer")
+
 // UserController handles HTTP requests for users
 type UserController struct {
 	userService service.UserService
@@ -26,34 +32,49 @@ func NewUserController(userService service.UserService) *UserController {
 }
 
 // Register registers the router for the user controller
-func (c *UserController) Register(router *gin.RouterGroup) {
+func (c *UserController) Register(router *registry.Group) {
 	users := router.Group("/users")
 	{
 		users.GET("", c.GetAllUsers)
+		users.GET("/summary", middleware.RequireAdmin(), c.GetSummary)
 		users.GET("/:id", c.GetUserByID)
 		users.POST("", c.CreateUser)
-		users.PUT("/:id", c.UpdateUser)
+		users.POST("/bulk-status", middleware.RequireAdmin(), c.BulkUpdateStatus)
+		users.POST("/by-emails", middleware.RequireAdmin(), c.LookupByEmails)
+		users.PUT("/:id", StrictJSON(), c.UpdateUser)
+		users.PUT("/:id/role", middleware.RequireAdmin(), StrictJSON(), c.ChangeUserRole)
 		users.DELETE("/:id", c.DeleteUser)
 	}
 }
 
-// GetAllUsers returns all users
+// GetAllUsers returns all users, or one page of them when pagination parameters are given
 // @Summary Get all users
-// @Description Get all users
+// @Description Get all users, paginated by offset/limit or page/page_size
 // @Tags users
 // @Accept json
 // @Produce json
+// @Param offset query int false "Rows to skip"
+// @Param limit query int false "Rows to return"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Rows per page"
 // @Success 200 {array} entities.UserResponse
+// @Failure 400 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /users [get]
 func (c *UserController) GetAllUsers(ctx *gin.Context) {
-	users, err := c.userService.GetAllUsers(ctx.Request.Context())
+	page, err := parsePage(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	users, err := c.userService.GetAllUsers(ctx.Request.Context(), page)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, users)
+	response.Send(ctx, http.StatusOK, capResponseItems(ctx, users))
 }
 
 // GetUserByID returns a user by ID
@@ -63,25 +84,42 @@ func (c *UserController) GetAllUsers(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
+// @Param include_deleted query bool false "Include soft-deleted users"
 // @Success 200 {object} entities.UserResponse
 // @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
 // @Failure 404 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /users/{id} [get]
 func (c *UserController) GetUserByID(ctx *gin.Context) {
 	id, err := parseIDParam(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
+		handleError(ctx, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
 		return
 	}
 
-	user, err := c.userService.GetUserByID(ctx.Request.Context(), id)
+	includeDeleted, err := strconv.ParseBool(ctx.DefaultQuery("include_deleted", "false"))
+	if err != nil {
+		handleError(ctx, apperrors.NewInvalidInputError("Invalid include_deleted value", nil, err))
+		return
+	}
+	if includeDeleted && !middleware.IsAdmin(ctx) {
+		handleError(ctx, apperrors.NewForbiddenError("Admin access required to include deleted users", nil))
+		return
+	}
+
+	var user *model.UserResponse
+	if includeDeleted {
+		user, err = c.userService.GetUserByIDIncludingDeleted(ctx.Request.Context(), id)
+	} else {
+		user, err = c.userService.GetUserByID(ctx.Request.Context(), id)
+	}
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	response.Send(ctx, http.StatusOK, user)
 }
 
 // CreateUser creates a new user
@@ -98,9 +136,9 @@ func (c *UserController) GetUserByID(ctx *gin.Context) {
 // @Router /users [post]
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	var input model.UserCreate
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		logger.Error("Invalid input for creating user", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, apperrors.NewInvalidInputError("Invalid input", nil, err))
+	if err := bindJSON(ctx, &input); err != nil {
+		logger.Error("Invalid input for creating user", validationErrorField(err))
+		handleError(ctx, err)
 		return
 	}
 
@@ -110,7 +148,7 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, user)
+	response.Send(ctx, http.StatusCreated, user)
 }
 
 // UpdateUser updates a user
@@ -129,14 +167,14 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 func (c *UserController) UpdateUser(ctx *gin.Context) {
 	id, err := parseIDParam(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
+		handleError(ctx, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
 		return
 	}
 
 	var input model.UserUpdate
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		logger.Error("Invalid input for updating user", zap.Error(err))
-		ctx.JSON(http.StatusBadRequest, apperrors.NewInvalidInputError("Invalid input", nil, err))
+	if err := bindJSON(ctx, &input); err != nil {
+		logger.Error("Invalid input for updating user", validationErrorField(err))
+		handleError(ctx, err)
 		return
 	}
 
@@ -146,7 +184,45 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	response.Send(ctx, http.StatusOK, user)
+}
+
+// ChangeUserRole changes a user's role
+// @Summary Change a user's role
+// @Description Change a user's role, refusing to demote the last active admin
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param input body model.UserRoleChange true "New role"
+// @Success 200 {object} entities.UserResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /users/{id}/role [put]
+func (c *UserController) ChangeUserRole(ctx *gin.Context) {
+	id, err := parseIDParam(ctx)
+	if err != nil {
+		handleError(ctx, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
+		return
+	}
+
+	var input model.UserRoleChange
+	if err := bindJSON(ctx, &input); err != nil {
+		logger.Error("Invalid input for changing user role", validationErrorField(err))
+		handleError(ctx, err)
+		return
+	}
+
+	user, err := c.userService.ChangeUserRole(ctx.Request.Context(), id, input.Role, middleware.ActorID(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response.Send(ctx, http.StatusOK, user)
 }
 
 // DeleteUser deletes a user
@@ -164,7 +240,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 func (c *UserController) DeleteUser(ctx *gin.Context) {
 	id, err := parseIDParam(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
+		handleError(ctx, apperrors.NewInvalidInputError("Invalid ID format", nil, err))
 		return
 	}
 
@@ -176,22 +252,119 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
-// Helper function to parse ID parameter
+// BulkUpdateStatus activates or deactivates several users
+// @Summary Bulk update user status
+// @Description Set the active flag for several users in one transaction
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body model.UserBulkStatusUpdate true "User IDs and status"
+// @Success 200 {object} model.BulkResult
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /users/bulk-status [post]
+func (c *UserController) BulkUpdateStatus(ctx *gin.Context) {
+	var input model.UserBulkStatusUpdate
+	if err := bindJSON(ctx, &input); err != nil {
+		logger.Error("Invalid input for bulk updating user status", validationErrorField(err))
+		handleError(ctx, err)
+		return
+	}
+
+	result, err := c.userService.BulkUpdateStatus(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response.Send(ctx, http.StatusOK, result)
+}
+
+// LookupByEmails resolves several emails to users
+// @Summary Look up users by email
+// @Description Resolve up to 100 emails to users in one call, matching case-insensitively
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body model.UserEmailLookup true "Emails to resolve"
+// @Success 200 {object} model.UserEmailLookupResult
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /users/by-emails [post]
+func (c *UserController) LookupByEmails(ctx *gin.Context) {
+	var input model.UserEmailLookup
+	if err := bindJSON(ctx, &input); err != nil {
+		logger.Error("Invalid input for looking up users by email", validationErrorField(err))
+		handleError(ctx, err)
+		return
+	}
+
+	result, err := c.userService.LookupByEmails(ctx.Request.Context(), input)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result.Users = capResponseItems(ctx, result.Users)
+
+	response.Send(ctx, http.StatusOK, result)
+}
+
+// GetSummary returns user counts by role and by active status
+// @Summary Summarize users
+// @Description Count users grouped by role and by active status
+// @Tags users
+// @Produce json
+// @Success 200 {object} model.UserSummary
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /users/summary [get]
+func (c *UserController) GetSummary(ctx *gin.Context) {
+	summary, err := c.userService.GetSummary(ctx.Request.Context())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	response.Send(ctx, http.StatusOK, summary)
+}
+
+// Helper function to parse ID parameter, rejecting negative, zero and overflowing values
 func parseIDParam(ctx *gin.Context) (uint, error) {
 	idParam := ctx.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
 		return 0, err
 	}
+	if id == 0 {
+		return 0, errInvalidID
+	}
 	return uint(id), nil
 }
 
 // Helper function to handle errors
 func handleError(ctx *gin.Context, err error) {
 	var appErr *apperrors.AppError
-	if stderrors.As(err, &appErr) {
-		ctx.JSON(appErr.StatusCode, appErr)
-		return
+	if !stderrors.As(err, &appErr) {
+		appErr = apperrors.NewInternalError("An unexpected error occurred", err)
+	}
+	writeError(ctx, appErr)
+}
+
+// Helper function to write an error as JSON, or as a "CODE: message" line for clients accepting only plain text
+func writeError(ctx *gin.Context, appErr *apperrors.AppError) {
+	status := appErr.StatusCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	response.SetRetryAfter(ctx.Writer.Header(), appErr)
+
+	switch ctx.NegotiateFormat(binding.MIMEJSON, binding.MIMEPlain) {
+	case binding.MIMEPlain:
+		ctx.String(status, "%s: %s\n", appErr.Code, appErr.Message)
+	default:
+		ctx.JSON(status, appErr)
 	}
-	ctx.JSON(http.StatusInternalServerError, apperrors.NewInternalError("An unexpected error occurred", err))
 }
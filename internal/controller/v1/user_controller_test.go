@@ -0,0 +1,464 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/middleware"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/router/registry"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserService is a mock implementation of service.UserService
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) GetAllUsers(ctx context.Context, page model.Page) ([]model.UserResponse, error) {
+	args := m.Called(ctx, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetUserByID(ctx context.Context, id uint) (*model.UserResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetUserByIDIncludingDeleted(ctx context.Context, id uint) (*model.UserResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) LookupByEmails(ctx context.Context, input model.UserEmailLookup) (*model.UserEmailLookupResult, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserEmailLookupResult), args.Error(1)
+}
+
+func (m *MockUserService) CreateUser(ctx context.Context, input model.UserCreate) (*model.UserResponse, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) UpdateUser(ctx context.Context, id uint, input model.UserUpdate) (*model.UserResponse, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) MustExist(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) BulkUpdateStatus(ctx context.Context, input model.UserBulkStatusUpdate) (*model.BulkResult, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BulkResult), args.Error(1)
+}
+
+func (m *MockUserService) GetSummary(ctx context.Context) (*model.UserSummary, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserSummary), args.Error(1)
+}
+
+func (m *MockUserService) ChangeUserRole(ctx context.Context, id uint, newRole string, actorID string) (*model.UserResponse, error) {
+	args := m.Called(ctx, id, newRole, actorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserResponse), args.Error(1)
+}
+
+// testAdminToken is the admin token accepted by newTestRouter
+const testAdminToken = "test-admin-token"
+
+// newTestRouter creates a router with the user routes registered
+func newTestRouter(userService *MockUserService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.AdminToken(testAdminToken))
+	NewUserController(userService).Register(registry.New().Wrap(router.Group("/api/v1")))
+	return router
+}
+
+func TestUserIDParsing(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{name: "Negative", id: "-1", expectedStatus: http.StatusBadRequest},
+		{name: "Zero", id: "0", expectedStatus: http.StatusBadRequest},
+		{name: "Overflow", id: "99999999999", expectedStatus: http.StatusBadRequest},
+		{name: "NotANumber", id: "abc", expectedStatus: http.StatusBadRequest},
+		{name: "Valid", id: "42", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Only the valid ID should reach the service
+			mockService := new(MockUserService)
+			mockService.On("GetUserByID", mock.Anything, uint(42)).Return(&model.UserResponse{ID: 42}, nil)
+			router := newTestRouter(mockService)
+
+			// Perform request
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/"+tc.id, nil))
+
+			// Assert results
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusBadRequest {
+				var body apperrors.AppError
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, apperrors.ErrCodeInvalidInput, body.Code)
+				mockService.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateStatusRejectsEmptyList(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	// Submit an empty list
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/bulk-status", strings.NewReader(`{"user_ids":[],"active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	router.ServeHTTP(w, req)
+
+	// Assert rejection before reaching the service
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "BulkUpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestBulkUpdateStatusMixedOutcome(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	// Setup mock with one updated, one failed and one missing user
+	result := model.NewBulkResult()
+	result.Succeed(1)
+	result.Fail(2, "user is locked")
+	result.Missing(3)
+	mockService.On("BulkUpdateStatus", mock.Anything, mock.Anything).Return(result, nil)
+
+	// Submit the batch
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/bulk-status", strings.NewReader(`{"user_ids":[1,2,3],"active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	router.ServeHTTP(w, req)
+
+	// Assert the shared bulk result shape
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"succeeded": [1],
+		"failed": [{"id": 2, "reason": "user is locked"}],
+		"not_found": [3],
+		"total": 3
+	}`, w.Body.String())
+}
+
+func TestBulkUpdateStatusRequiresAdmin(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	// Submit a valid batch without the admin token
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/bulk-status", strings.NewReader(`{"user_ids":[1,2,3],"active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert rejection without reaching the service
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "FORBIDDEN")
+	mockService.AssertNotCalled(t, "BulkUpdateStatus", mock.Anything, mock.Anything)
+}
+
+func TestGetUserByIDIncludeDeleted(t *testing.T) {
+	deletedAt := model.NewTimestamp(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		name           string
+		query          string
+		admin          bool
+		setupMock      func(*MockUserService)
+		expectedStatus int
+	}{
+		{
+			name:  "Default",
+			query: "",
+			setupMock: func(m *MockUserService) {
+				m.On("GetUserByID", mock.Anything, uint(1)).
+					Return(nil, apperrors.NewResourceNotFoundError("User not found", nil, nil))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:  "IncludeDeleted",
+			query: "?include_deleted=true",
+			admin: true,
+			setupMock: func(m *MockUserService) {
+				m.On("GetUserByIDIncludingDeleted", mock.Anything, uint(1)).
+					Return(&model.UserResponse{ID: 1, Name: "John Doe", DeletedAt: &deletedAt}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "IncludeDeletedNotAdmin",
+			query:          "?include_deleted=true",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "InvalidFlag",
+			query:          "?include_deleted=maybe",
+			setupMock:      func(m *MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tc.setupMock(mockService)
+			router := newTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users/1"+tc.query, nil)
+			if tc.admin {
+				req.Header.Set("Authorization", "Bearer "+testAdminToken)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"deleted_at":"2024-01-02T00:00:00Z"`)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLookupByEmailsCap(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	// Submit one email more than allowed
+	emails := make([]string, model.MaxEmailLookup+1)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	body, _ := json.Marshal(model.UserEmailLookup{Emails: emails})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/by-emails", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	router.ServeHTTP(w, req)
+
+	// Assert rejection without reaching the service
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_INPUT")
+	mockService.AssertNotCalled(t, "LookupByEmails", mock.Anything, mock.Anything)
+}
+
+func TestLookupByEmailsRequiresAdmin(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	// Submit a valid lookup without the admin token
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/by-emails", strings.NewReader(`{"emails":["john@example.com"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	// Assert rejection without reaching the service
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "FORBIDDEN")
+	mockService.AssertNotCalled(t, "LookupByEmails", mock.Anything, mock.Anything)
+}
+
+func TestGetSummary(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("GetSummary", mock.Anything).Return(&model.UserSummary{
+		Total:    3,
+		Active:   2,
+		Inactive: 1,
+		ByRole:   map[string]int64{model.RoleAdmin: 1, model.RoleUser: 2},
+	}, nil)
+	router := newTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/summary", nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	router.ServeHTTP(w, req)
+
+	// Assert the summary route isn't taken for a user ID
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"total":3,"active":2,"inactive":1,"by_role":{"admin":1,"user":2}}`, w.Body.String())
+	mockService.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetSummaryRequiresAdmin(t *testing.T) {
+	mockService := new(MockUserService)
+	router := newTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/summary", nil))
+
+	// Assert rejection without reaching the service
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertNotCalled(t, "GetSummary", mock.Anything)
+	mockService.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func TestChangeUserRoleRoutes(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("ChangeUserRole", mock.Anything, uint(1), "admin", middleware.ActorID(adminContext())).
+		Return(&model.UserResponse{ID: 1, Role: "admin"}, nil)
+	router := newTestRouter(mockService)
+
+	send := func(method, url, body string, admin bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if admin {
+			req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The generic update has no role field
+	w := send(http.MethodPut, "/api/v1/users/1", `{"name":"John","role":"admin"}`, true)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Unknown field \"role\"`)
+	mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+
+	// The dedicated endpoint is admin only
+	w = send(http.MethodPut, "/api/v1/users/1/role", `{"role":"admin"}`, false)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// It changes roles on behalf of the admin
+	w = send(http.MethodPut, "/api/v1/users/1/role", `{"role":"admin"}`, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"admin"`)
+
+	// A role is required
+	w = send(http.MethodPut, "/api/v1/users/1/role", `{}`, true)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "ChangeUserRole", 1)
+}
+
+// adminContext returns a context the test admin token was accepted on
+func adminContext() *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer "+testAdminToken)
+	middleware.AdminToken(testAdminToken)(ctx)
+	return ctx
+}
+
+func TestErrorContentNegotiation(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("GetUserByID", mock.Anything, uint(7)).
+		Return(nil, apperrors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": 7}, nil))
+	router := newTestRouter(mockService)
+
+	testCases := []struct {
+		name         string
+		accept       string
+		expectedType string
+		expectedBody string
+	}{
+		{name: "Default", accept: "", expectedType: "application/json", expectedBody: `{"code":"RESOURCE_NOT_FOUND","message":"User not found","details":{"id":7},"retryable":false}`},
+		{name: "JSON", accept: "application/json", expectedType: "application/json", expectedBody: `{"code":"RESOURCE_NOT_FOUND","message":"User not found","details":{"id":7},"retryable":false}`},
+		{name: "PlainText", accept: "text/plain", expectedType: "text/plain", expectedBody: "RESOURCE_NOT_FOUND: User not found\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users/7", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			router.ServeHTTP(w, req)
+
+			// Assert the same status in either representation
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), tc.expectedType)
+			if tc.expectedType == "application/json" {
+				assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			} else {
+				assert.Equal(t, tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrorRetryAfter(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("GetUserByID", mock.Anything, uint(7)).
+		Return(nil, apperrors.NewServiceUnavailableError("Database temporarily unavailable", nil).WithRetryAfter(30*time.Second))
+	mockService.On("GetUserByID", mock.Anything, uint(8)).
+		Return(nil, apperrors.NewResourceNotFoundError("User not found", nil, nil))
+	router := newTestRouter(mockService)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// An open circuit breaker advertises its remaining cooldown
+	w := request("/api/v1/users/7")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	// Errors that won't resolve by waiting don't
+	w = request("/api/v1/users/8")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
 // NewPostgresDB establishes a connection to the database
@@ -28,6 +29,15 @@ func NewPostgresDB(config *config.DatabaseConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Route read queries to the replica if configured
+	if config.ReplicaDSN != "" {
+		if err := RegisterReplica(db, postgres.Open(config.ReplicaDSN)); err != nil {
+			logger.Error("Failed to register read replica", zap.Error(err))
+			return nil, err
+		}
+		logger.Info("Read replica configured")
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -51,6 +61,14 @@ func NewPostgresDB(config *config.DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// RegisterReplica routes read queries to the replica while writes and transactions stay on the primary
+func RegisterReplica(db *gorm.DB, replica gorm.Dialector) error {
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{replica},
+		Policy:   dbresolver.RandomPolicy{},
+	}))
+}
+
 // CloseDatabaseConnection closes the database connection
 func CloseDatabaseConnection(db *gorm.DB) {
 	if db != nil {
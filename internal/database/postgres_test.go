@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openSQLite opens a file-backed SQLite database with the user schema migrated
+func openSQLite(t *testing.T, path string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("failed to migrate %s: %v", path, err)
+	}
+	return db
+}
+
+func TestRegisterReplica(t *testing.T) {
+	dir := t.TempDir()
+	primaryPath := filepath.Join(dir, "primary.db")
+	replicaPath := filepath.Join(dir, "replica.db")
+
+	// Seed a row that only exists on the replica
+	replica := openSQLite(t, replicaPath)
+	assert.NoError(t, replica.Create(&model.User{Name: "Replica User", Email: "replica@example.com", Password: "x"}).Error)
+
+	// Route reads of the primary connection to the replica
+	primary := openSQLite(t, primaryPath)
+	assert.NoError(t, RegisterReplica(primary, sqlite.Open(replicaPath)))
+
+	repo := repository.NewUserRepository(primary)
+	ctx := context.Background()
+
+	// Writes hit the primary
+	assert.NoError(t, primary.Create(&model.User{Name: "Primary User", Email: "primary@example.com", Password: "x"}).Error)
+
+	var primaryCount, replicaCount int64
+	openSQLite(t, primaryPath).Model(&model.User{}).Count(&primaryCount)
+	replica.Model(&model.User{}).Count(&replicaCount)
+	assert.Equal(t, int64(1), primaryCount)
+	assert.Equal(t, int64(1), replicaCount)
+
+	// Reads hit the replica
+	users, err := repo.FindAll(ctx, model.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "replica@example.com", users[0].Email)
+
+	user, err := repo.FindByID(ctx, users[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Replica User", user.Name)
+}
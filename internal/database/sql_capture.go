@@ -0,0 +1,37 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// sqlCaptureCallback names the callback recording statements after every other callback has run
+const sqlCaptureCallback = "sql_capture:record"
+
+// EnableSQLCapture makes the database record executed statements into the
+// SQL collector carried by each query's context. Statements are recorded with
+// their placeholders rather than the bound values, so password hashes and emails
+// never reach the request log.
+func EnableSQLCapture(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("*").Register(sqlCaptureCallback, captureSQL),
+		callbacks.Query().After("*").Register(sqlCaptureCallback, captureSQL),
+		callbacks.Update().After("*").Register(sqlCaptureCallback, captureSQL),
+		callbacks.Delete().After("*").Register(sqlCaptureCallback, captureSQL),
+		callbacks.Row().After("*").Register(sqlCaptureCallback, captureSQL),
+		callbacks.Raw().After("*").Register(sqlCaptureCallback, captureSQL),
+	)
+}
+
+// captureSQL records the statement if the context carries a collector
+func captureSQL(db *gorm.DB) {
+	if db.Statement.SQL.Len() == 0 {
+		return
+	}
+	if collector := logger.SQLCollectorFromContext(db.Statement.Context); collector != nil {
+		collector.Add(db.Statement.SQL.String())
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/repository"
+	applogger "github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSQLCaptureOmitsValues(t *testing.T) {
+	db := openSQLite(t, filepath.Join(t.TempDir(), "capture.db"))
+	assert.NoError(t, EnableSQLCapture(db))
+	repo := repository.NewUserRepository(db)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := &model.User{Name: "John Doe", Email: "john@example.com", Password: string(hash), Role: model.RoleUser, Active: true}
+
+	// Write and read the user while collecting the executed SQL
+	ctx, collector := applogger.ContextWithSQLCollector(context.Background())
+	assert.NoError(t, repo.Create(ctx, user))
+	user.Password = "$2a$10$rotated"
+	assert.NoError(t, repo.Update(ctx, user))
+	_, err = repo.FindByEmail(ctx, user.Email)
+	assert.NoError(t, err)
+
+	// Assert the statements were captured with placeholders instead of the values
+	queries, dropped := collector.Queries()
+	assert.Zero(t, dropped)
+	assert.NotEmpty(t, queries)
+	for _, query := range queries {
+		assert.NotContains(t, query, string(hash))
+		assert.NotContains(t, query, "$2a$")
+		assert.NotContains(t, query, "john@example.com")
+	}
+	assert.Contains(t, strings.Join(queries, "\n"), "LOWER(email) = LOWER(?)")
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+	"gorm.io/gorm"
+)
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+// CachedCheck reuses the last result of a check until it is older than the TTL
+type CachedCheck struct {
+	check     Check
+	ttl       time.Duration
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+	now       func() time.Time
+}
+
+// NewCachedCheck wraps check so that it runs at most once per ttl
+func NewCachedCheck(check Check, ttl time.Duration) *CachedCheck {
+	return &CachedCheck{
+		check: check,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// Run returns the cached result, running the check again once the TTL has passed
+func (c *CachedCheck) Run(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.checkedAt.IsZero() && now.Sub(c.checkedAt) < c.ttl {
+		return c.err
+	}
+
+	c.err = c.check(ctx)
+	c.checkedAt = now
+	return c.err
+}
+
+// Checker runs named dependency checks for the readiness probe
+type Checker struct {
+	names   []string
+	checks  map[string]*CachedCheck
+	ttl     time.Duration
+	timeout time.Duration
+}
+
+// NewChecker creates a checker caching each result for ttl and bounding each check by timeout
+func NewChecker(ttl, timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  make(map[string]*CachedCheck),
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+// Add registers a named dependency check
+func (c *Checker) Add(name string, check Check) {
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = NewCachedCheck(check, c.ttl)
+}
+
+// Run runs every check and returns each dependency's status and whether all are healthy
+func (c *Checker) Run(ctx context.Context) (map[string]string, bool) {
+	statuses := make(map[string]string, len(c.names))
+	healthy := true
+	for _, name := range c.names {
+		checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := c.checks[name].Run(checkCtx)
+		cancel()
+
+		if err != nil {
+			statuses[name] = err.Error()
+			healthy = false
+			continue
+		}
+		statuses[name] = "ok"
+	}
+	return statuses, healthy
+}
+
+// Handler responds 200 when every dependency is healthy and 503 otherwise,
+// asking clients to retry once the cached results expire
+func (c *Checker) Handler(ctx *gin.Context) {
+	statuses, healthy := c.Run(ctx.Request.Context())
+	if !healthy {
+		appErr := apperrors.NewServiceUnavailableError("Dependencies unavailable", nil).WithRetryAfter(c.ttl)
+		response.SetRetryAfter(ctx.Writer.Header(), appErr)
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "unavailable",
+			"checks": statuses,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"checks": statuses,
+	})
+}
+
+// errRollback aborts the write check's transaction after a successful write
+var errRollback = errors.New("rollback health check write")
+
+// DatabaseWriteCheck confirms the database accepts writes by inserting a row and rolling it back.
+// It catches a pingable but read-only database, such as a replica promoted incorrectly after failover.
+func DatabaseWriteCheck(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&model.HealthCheck{CheckedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if errors.Is(err, errRollback) {
+			return nil
+		}
+		return fmt.Errorf("database is not writable: %w", err)
+	}
+}
+
+// DatabaseCheck pings the database
+func DatabaseCheck(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database connection: %w", err)
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
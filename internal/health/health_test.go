@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCachedCheckTTL(t *testing.T) {
+	// Create a counting check with a controllable clock
+	calls := 0
+	var checkErr error
+	cached := NewCachedCheck(func(context.Context) error {
+		calls++
+		return checkErr
+	}, 5*time.Second)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return now }
+
+	// First probe runs the check
+	assert.NoError(t, cached.Run(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	// Probes within the TTL reuse the result, even if the dependency went down
+	checkErr = errors.New("connection refused")
+	now = now.Add(4 * time.Second)
+	assert.NoError(t, cached.Run(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	// After the TTL the check runs again and reports the failure
+	now = now.Add(time.Second)
+	assert.EqualError(t, cached.Run(context.Background()), "connection refused")
+	assert.Equal(t, 2, calls)
+}
+
+func TestCheckerHandler(t *testing.T) {
+	testCases := []struct {
+		name               string
+		ttl                time.Duration
+		cacheErr           error
+		expectedStatus     int
+		expectedBody       string
+		expectedRetryAfter string
+	}{
+		{
+			name:           "Healthy",
+			ttl:            time.Second,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{"database":"ok","cache":"ok"}}`,
+		},
+		{
+			name:               "DependencyDown",
+			ttl:                2500 * time.Millisecond,
+			cacheErr:           errors.New("connection refused"),
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       `{"status":"unavailable","checks":{"database":"ok","cache":"connection refused"}}`,
+			expectedRetryAfter: "3",
+		},
+		{
+			name:               "DependencyDownUncached",
+			cacheErr:           errors.New("connection refused"),
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       `{"status":"unavailable","checks":{"database":"ok","cache":"connection refused"}}`,
+			expectedRetryAfter: "1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := NewChecker(tc.ttl, time.Second)
+			checker.Add("database", func(context.Context) error { return nil })
+			checker.Add("cache", func(context.Context) error { return tc.cacheErr })
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/readyz", checker.Handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			assert.Equal(t, tc.expectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestDatabaseWriteCheck(t *testing.T) {
+	// Create a migrated database file
+	path := filepath.Join(t.TempDir(), "app.db")
+	writable, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, migration.AutoMigrate(writable))
+
+	// Open a read-only handle to the same database
+	readOnly, err := gorm.Open(sqlite.Open("file:"+path+"?mode=ro"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		for _, db := range []*gorm.DB{writable, readOnly} {
+			sqlDB, _ := db.DB()
+			_ = sqlDB.Close()
+		}
+	})
+
+	ctx := context.Background()
+
+	// The writable handle passes both checks without leaving rows behind
+	assert.NoError(t, DatabaseCheck(writable)(ctx))
+	assert.NoError(t, DatabaseWriteCheck(writable)(ctx))
+	var count int64
+	assert.NoError(t, writable.Model(&model.HealthCheck{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// The read-only handle is pingable but not writable
+	assert.NoError(t, DatabaseCheck(readOnly)(ctx))
+	assert.ErrorContains(t, DatabaseWriteCheck(readOnly)(ctx), "database is not writable")
+}
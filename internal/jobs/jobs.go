@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWorkers is the number of jobs run concurrently when unconfigured
+	DefaultWorkers = 4
+	// DefaultQueueSize is the number of jobs waiting for a worker when unconfigured
+	DefaultQueueSize = 100
+)
+
+var (
+	// ErrQueueFull is returned when every worker is busy and the queue has no room left
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrQueueClosed is returned once the queue has stopped accepting jobs
+	ErrQueueClosed = errors.New("job queue is closed")
+)
+
+// Job is a task run outside the request that enqueued it, such as sending an email
+type Job func(ctx context.Context)
+
+// Queue is a bounded in-process queue drained by a fixed pool of workers
+type Queue struct {
+	workers int
+	jobs    chan Job
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// NewQueue creates a queue run by workers goroutines, holding at most size pending jobs
+func NewQueue(workers, size int) *Queue {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if size < 0 {
+		size = DefaultQueueSize
+	}
+	return &Queue{
+		workers: workers,
+		jobs:    make(chan Job, size),
+	}
+}
+
+// Enqueue schedules job without waiting for it, failing with ErrQueueFull rather than blocking the caller
+func (q *Queue) Enqueue(job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		logger.Warn("Rejected job, queue is full", zap.Int("queue_size", cap(q.jobs)))
+		return ErrQueueFull
+	}
+}
+
+// Usage reports the jobs waiting for a worker and the queue size
+func (q *Queue) Usage() (queued, size int) {
+	return len(q.jobs), cap(q.jobs)
+}
+
+// Run starts the workers and blocks until ctx is cancelled. It then stops accepting jobs
+// and returns once the in-flight and already queued jobs have finished.
+// Jobs get a context that outlives ctx, so shutdown doesn't abort them halfway.
+func (q *Queue) Run(ctx context.Context) error {
+	jobCtx := context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range q.jobs {
+				run(jobCtx, job)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	q.mu.Lock()
+	q.closed = true
+	close(q.jobs)
+	q.mu.Unlock()
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// run executes job, logging a panic instead of losing the worker
+func run(ctx context.Context, job Job) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("Job panicked", zap.Any("panic", recovered))
+		}
+	}()
+	job(ctx)
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultQueue *Queue
+)
+
+// SetDefault sets the queue used by Enqueue, it is configured once at boot
+func SetDefault(q *Queue) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultQueue = q
+}
+
+// Enqueue schedules job on the default queue, failing with ErrQueueClosed when none is running
+func Enqueue(job Job) error {
+	defaultMu.RLock()
+	q := defaultQueue
+	defaultMu.RUnlock()
+
+	if q == nil {
+		return ErrQueueClosed
+	}
+	return q.Enqueue(job)
+}
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startQueue runs q until the returned stop function cancels it and waits for Run to return
+func startQueue(q *Queue) (stop func() error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- q.Run(ctx) }()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func TestQueueRunsEnqueuedJobs(t *testing.T) {
+	q := NewQueue(2, 10)
+	stop := startQueue(q)
+	defer stop()
+
+	// Enqueue several jobs
+	var ran atomic.Int32
+	done := make(chan struct{}, 5)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, q.Enqueue(func(ctx context.Context) {
+			ran.Add(1)
+			done <- struct{}{}
+		}))
+	}
+
+	// Assert every job ran
+	for i := 0; i < 5; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+	assert.Equal(t, int32(5), ran.Load())
+}
+
+func TestQueueShutdownWaitsForJobs(t *testing.T) {
+	q := NewQueue(1, 10)
+	stop := startQueue(q)
+
+	// Enqueue a slow job and one waiting behind it
+	started := make(chan struct{})
+	var finished atomic.Int32
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, ctx.Err())
+		finished.Add(1)
+	}))
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) {
+		finished.Add(1)
+	}))
+	<-started
+
+	// Shut down while the first job is in flight
+	err := stop()
+
+	// Assert both jobs finished before Run returned, and later jobs are refused
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(2), finished.Load())
+	assert.ErrorIs(t, q.Enqueue(func(ctx context.Context) {}), ErrQueueClosed)
+}
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	q := NewQueue(1, 1)
+	stop := startQueue(q)
+	defer stop()
+
+	// Occupy the only worker
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	// Fill the queue, then overflow it
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) {}))
+	err := q.Enqueue(func(ctx context.Context) {})
+
+	assert.ErrorIs(t, err, ErrQueueFull)
+	close(release)
+}
+
+func TestQueueRecoversPanics(t *testing.T) {
+	q := NewQueue(1, 10)
+	stop := startQueue(q)
+	defer stop()
+
+	// Enqueue a panicking job followed by a normal one
+	done := make(chan struct{})
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) { panic("boom") }))
+	assert.NoError(t, q.Enqueue(func(ctx context.Context) { close(done) }))
+
+	// Assert the worker survived
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not survive the panic")
+	}
+}
+
+func TestEnqueueWithoutDefaultQueue(t *testing.T) {
+	SetDefault(nil)
+
+	err := Enqueue(func(ctx context.Context) {})
+
+	assert.ErrorIs(t, err, ErrQueueClosed)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	testCases := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "ValidToken", token: "s3cr3t", authorization: "Bearer s3cr3t", expectedStatus: http.StatusOK},
+		{name: "WrongToken", token: "s3cr3t", authorization: "Bearer guess", expectedStatus: http.StatusForbidden},
+		{name: "MissingHeader", token: "s3cr3t", expectedStatus: http.StatusForbidden},
+		{name: "NotBearer", token: "s3cr3t", authorization: "s3cr3t", expectedStatus: http.StatusForbidden},
+		{name: "NoTokenConfigured", authorization: "Bearer ", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create router with one admin route
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(AdminToken(tc.token))
+			router.GET("/admin", RequireAdmin(), func(c *gin.Context) {
+				c.String(http.StatusOK, ActorID(c))
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, adminActorID(tc.token), w.Body.String())
+				assert.NotContains(t, w.Body.String(), tc.token)
+			}
+			if tc.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"FORBIDDEN"`)
+			}
+		})
+	}
+}
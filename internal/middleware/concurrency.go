@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+)
+
+// UserIDKey is the context key authentication middleware sets to the caller's user ID
+const UserIDKey = "user_id"
+
+// ConcurrencyLimiter caps the in-flight requests of each user
+type ConcurrencyLimiter struct {
+	limit    int
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+// NewConcurrencyLimiter creates a limiter allowing limit simultaneous requests per key
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		limit:    limit,
+		inFlight: make(map[string]int),
+	}
+}
+
+// acquire reserves a slot for key, reporting false when all are taken
+func (l *ConcurrencyLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] >= l.limit {
+		return false
+	}
+	l.inFlight[key]++
+	return true
+}
+
+// release frees a slot for key, forgetting keys with nothing in flight
+func (l *ConcurrencyLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight[key]--
+	if l.inFlight[key] <= 0 {
+		delete(l.inFlight, key)
+	}
+}
+
+// LimitConcurrency rejects requests with 429 while the caller already has the limit in flight.
+// Callers are keyed by the authenticated user ID, or by client IP when unauthenticated.
+func LimitConcurrency(limiter *ConcurrencyLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := concurrencyKey(c)
+		if !limiter.acquire(key) {
+			appErr := apperrors.NewRateLimitedError(
+				"Too many concurrent requests",
+				map[string]interface{}{"limit": limiter.limit},
+			)
+			response.SetRetryAfter(c.Writer.Header(), appErr)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, appErr)
+			return
+		}
+		defer limiter.release(key)
+
+		c.Next()
+	}
+}
+
+// concurrencyKey identifies the caller, keeping user and IP keys from colliding
+func concurrencyKey(c *gin.Context) string {
+	if userID, ok := c.Get(UserIDKey); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
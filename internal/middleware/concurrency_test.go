@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitConcurrency(t *testing.T) {
+	// Create router whose handler blocks until released, authenticating from a header
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-User"); userID != "" {
+			c.Set(UserIDKey, userID)
+		}
+	})
+	limiter := NewConcurrencyLimiter(2)
+	router.Use(LimitConcurrency(limiter))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(path, user string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Saturate alice's limit with in-flight requests
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, request("/slow", "alice"))
+		}()
+		<-started
+	}
+
+	// Alice is rejected while bob and unauthenticated callers from the same IP are not
+	assert.Equal(t, http.StatusTooManyRequests, request("/fast", "alice"))
+	assert.Equal(t, http.StatusOK, request("/fast", "bob"))
+	assert.Equal(t, http.StatusOK, request("/fast", ""))
+
+	// Finishing her requests frees alice's slots
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, request("/fast", "alice"))
+	assert.Empty(t, limiter.inFlight)
+}
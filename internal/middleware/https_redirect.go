@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderForwardedProto is set by TLS-terminating proxies to the scheme the client used
+const HeaderForwardedProto = "X-Forwarded-Proto"
+
+// HTTPSRedirect permanently redirects plain HTTP requests to their https:// URL with 308, preserving the method and body.
+// A request is secure when the server terminated TLS itself or the proxy reports https; exemptPaths, such as health checks, are never redirected.
+func HTTPSRedirect(exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil || forwardedProto(c) == "https" || exempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		c.Redirect(http.StatusPermanentRedirect, "https://"+c.Request.Host+c.Request.URL.RequestURI())
+		c.Abort()
+	}
+}
+
+// forwardedProto returns the scheme reported by the closest proxy, lowercased
+func forwardedProto(c *gin.Context) string {
+	proto := c.GetHeader(HeaderForwardedProto)
+	// Proxies chained together append their schemes, the first one is the client's
+	if i := strings.IndexByte(proto, ','); i >= 0 {
+		proto = proto[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(proto))
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(HTTPSRedirect("/health"))
+	router.Any("/users", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	testCases := []struct {
+		name             string
+		method           string
+		url              string
+		forwardedProto   string
+		tls              bool
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "ForwardedHTTP", method: http.MethodGet, url: "/users?page=2", forwardedProto: "http", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "https://api.example.com/users?page=2"},
+		{name: "ForwardedHTTPPost", method: http.MethodPost, url: "/users", forwardedProto: "http", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "https://api.example.com/users"},
+		{name: "PlainHTTP", method: http.MethodGet, url: "/users", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "https://api.example.com/users"},
+		{name: "ForwardedHTTPS", method: http.MethodGet, url: "/users", forwardedProto: "https", expectedStatus: http.StatusOK},
+		{name: "ChainedProxiesHTTPS", method: http.MethodGet, url: "/users", forwardedProto: "HTTPS, http", expectedStatus: http.StatusOK},
+		{name: "DirectTLS", method: http.MethodGet, url: "/users", tls: true, expectedStatus: http.StatusOK},
+		{name: "ExemptHealthCheck", method: http.MethodGet, url: "/health", forwardedProto: "http", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.url, nil)
+			req.Host = "api.example.com"
+			if tc.forwardedProto != "" {
+				req.Header.Set(HeaderForwardedProto, tc.forwardedProto)
+			}
+			if tc.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
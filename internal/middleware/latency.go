@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultLatencySampleSize bounds the durations kept per route between summaries
+const DefaultLatencySampleSize = 1024
+
+// LatencyAggregator collects request durations per route and periodically logs their percentiles.
+// Each route keeps a fixed-size reservoir sample so memory stays bounded under any load.
+type LatencyAggregator struct {
+	sampleSize int
+	mu         sync.Mutex
+	routes     map[string]*latencyReservoir
+}
+
+type latencyReservoir struct {
+	count   int
+	samples []time.Duration
+}
+
+// LatencySummary holds the percentiles of one route's durations
+type LatencySummary struct {
+	Route string
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// NewLatencyAggregator creates an aggregator keeping up to sampleSize durations per route
+func NewLatencyAggregator(sampleSize int) *LatencyAggregator {
+	return &LatencyAggregator{
+		sampleSize: sampleSize,
+		routes:     make(map[string]*latencyReservoir),
+	}
+}
+
+// Record adds a request duration for a route
+func (a *LatencyAggregator) Record(route string, duration time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r, ok := a.routes[route]
+	if !ok {
+		r = &latencyReservoir{samples: make([]time.Duration, 0, a.sampleSize)}
+		a.routes[route] = r
+	}
+	r.count++
+
+	// Reservoir sampling keeps every duration with equal probability
+	if len(r.samples) < a.sampleSize {
+		r.samples = append(r.samples, duration)
+		return
+	}
+	if i := rand.IntN(r.count); i < a.sampleSize {
+		r.samples[i] = duration
+	}
+}
+
+// Flush returns the summary of every route and resets the collected durations
+func (a *LatencyAggregator) Flush() []LatencySummary {
+	a.mu.Lock()
+	routes := a.routes
+	a.routes = make(map[string]*latencyReservoir)
+	a.mu.Unlock()
+
+	summaries := make([]LatencySummary, 0, len(routes))
+	for route, r := range routes {
+		slices.Sort(r.samples)
+		summaries = append(summaries, LatencySummary{
+			Route: route,
+			Count: r.count,
+			P50:   percentile(r.samples, 50),
+			P95:   percentile(r.samples, 95),
+			P99:   percentile(r.samples, 99),
+		})
+	}
+	slices.SortFunc(summaries, func(a, b LatencySummary) int {
+		return cmp.Compare(a.Route, b.Route)
+	})
+	return summaries
+}
+
+// Log logs the summary of every route and resets the collected durations
+func (a *LatencyAggregator) Log() {
+	for _, summary := range a.Flush() {
+		logger.Info("Request latency summary",
+			zap.String("route", summary.Route),
+			zap.Int("count", summary.Count),
+			zap.Duration("p50", summary.P50),
+			zap.Duration("p95", summary.P95),
+			zap.Duration("p99", summary.P99),
+		)
+	}
+}
+
+// Run logs a summary every interval until ctx is cancelled
+func (a *LatencyAggregator) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.Log()
+			return nil
+		case <-ticker.C:
+			a.Log()
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// RecordLatency records the duration of each request in the aggregator, keyed by method and route
+func RecordLatency(aggregator *LatencyAggregator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		aggregator.Record(c.Request.Method+" "+route, time.Since(start))
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyAggregatorPercentiles(t *testing.T) {
+	logs := observeLogs(t)
+	aggregator := NewLatencyAggregator(DefaultLatencySampleSize)
+
+	// Feed 1ms..1000ms for one route and a constant for another
+	for i := 1000; i >= 1; i-- {
+		aggregator.Record("GET /api/v1/users", time.Duration(i)*time.Millisecond)
+	}
+	aggregator.Record("GET /health", 2*time.Millisecond)
+
+	aggregator.Log()
+
+	// Assert one summary per route with percentiles within tolerance
+	entries := logs.FilterMessage("Request latency summary").All()
+	assert.Len(t, entries, 2)
+
+	users := entries[0].ContextMap()
+	assert.Equal(t, "GET /api/v1/users", users["route"])
+	assert.Equal(t, int64(1000), users["count"])
+	assert.InDelta(t, 500*time.Millisecond, users["p50"], float64(5*time.Millisecond))
+	assert.InDelta(t, 950*time.Millisecond, users["p95"], float64(5*time.Millisecond))
+	assert.InDelta(t, 990*time.Millisecond, users["p99"], float64(5*time.Millisecond))
+
+	health := entries[1].ContextMap()
+	assert.Equal(t, "GET /health", health["route"])
+	assert.Equal(t, 2*time.Millisecond, health["p99"])
+
+	// Assert the durations were reset
+	assert.Empty(t, aggregator.Flush())
+}
+
+func TestLatencyAggregatorBoundedMemory(t *testing.T) {
+	aggregator := NewLatencyAggregator(100)
+
+	// Record far more durations than the sample holds
+	for i := 1; i <= 100000; i++ {
+		aggregator.Record("GET /api/v1/users", time.Duration(i%1000)*time.Millisecond)
+	}
+
+	assert.Len(t, aggregator.routes["GET /api/v1/users"].samples, 100)
+
+	summaries := aggregator.Flush()
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, 100000, summaries[0].Count)
+	assert.InDelta(t, 500*time.Millisecond, summaries[0].P50, float64(250*time.Millisecond))
+}
+
+func TestRecordLatency(t *testing.T) {
+	aggregator := NewLatencyAggregator(DefaultLatencySampleSize)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RecordLatency(aggregator))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	// Assert requests are grouped by route pattern
+	summaries := aggregator.Flush()
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, "GET /users/:id", summaries[0].Route)
+	assert.Equal(t, 2, summaries[0].Count)
+	assert.Equal(t, "GET unmatched", summaries[1].Route)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// responseSize tracks response body sizes per route.
+// It is observed once per request by RequestLogger, so compression middleware never counts a response twice.
+var responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_response_size_bytes",
+	Help:    "Size of HTTP response bodies in bytes, before any transport compression.",
+	Buckets: prometheus.ExponentialBuckets(64, 4, 8), // 64B to 1MB
+}, []string{"method", "route"})
+
+func init() {
+	prometheus.MustRegister(responseSize)
+}
+
+// observeResponseSize records the response size, grouping unmatched paths so they can't inflate cardinality
+func observeResponseSize(c *gin.Context, size int) {
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	responseSize.WithLabelValues(c.Request.Method, route).Observe(float64(size))
+}
+
+// Metrics serves the Prometheus metrics of the default registry
+func Metrics() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
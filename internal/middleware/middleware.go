@@ -2,36 +2,107 @@ package middleware
 
 import (
 	"bytes"
+	"context"
+	"github.com/ladderseeker/gin-crud-starter/config"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
 	"io"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // SetupMiddleware configures middleware for the router
-func SetupMiddleware(router *gin.Engine) {
+func SetupMiddleware(router *gin.Engine, conf *config.Config) {
+	requestIDHeader := conf.Server.RequestIDHeader
+	if requestIDHeader == "" {
+		requestIDHeader = DefaultRequestIDHeader
+	}
+
+	// Reject oversized URLs before anything else handles them
+	maxURLLength := conf.Server.MaxURLLength
+	if maxURLLength <= 0 {
+		maxURLLength = DefaultMaxURLLength
+	}
+	router.Use(MaxURLLength(maxURLLength))
+
 	// CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", requestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", requestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// HTTPS redirect middleware, health checks stay reachable over plain HTTP for probes
+	if conf.Server.HTTPSRedirect {
+		router.Use(HTTPSRedirect("/health", "/readyz"))
+	}
+
+	// Security headers middleware, HSTS is only meaningful when this server terminates TLS
+	if conf.Server.SecurityHeaders.Enabled {
+		headers := conf.Server.SecurityHeaders
+		if !conf.Server.TLS.Enabled() {
+			headers.HSTSMaxAge = 0
+		}
+		router.Use(SecurityHeaders(headers))
+	}
+
+	// Request ID middleware
+	router.Use(RequestID(requestIDHeader))
+
+	// Admin token middleware, marks callers allowed on admin-only routes
+	router.Use(AdminToken(conf.Server.AdminToken))
+
 	// Request logging middleware
-	router.Use(RequestLogger())
+	router.Use(RequestLogger(&conf.Logging))
 
 	// Recovery middleware
 	router.Use(gin.Recovery())
+
+	// Response timeout middleware
+	if timeout := conf.Server.HandlerTimeout(); timeout > 0 {
+		router.Use(Timeout(timeout))
+	}
+
+	// Rate limiting middleware
+	if conf.RateLimit.Enabled {
+		router.Use(RateLimit(newRateLimiter(&conf.RateLimit)))
+	}
+
+	// Per-user concurrency limiting middleware, off by default since no authentication middleware sets UserIDKey yet
+	if conf.RateLimit.MaxConcurrentPerUser > 0 {
+		router.Use(LimitConcurrency(NewConcurrencyLimiter(conf.RateLimit.MaxConcurrentPerUser)))
+	}
 }
 
-// RequestLogger logs request and response details
-func RequestLogger() gin.HandlerFunc {
+// newRateLimiter shares budgets through Redis when REDIS_URL is set, otherwise keeps them in memory
+func newRateLimiter(conf *config.RateLimitConfig) RateLimiter {
+	memory := NewMemoryRateLimiter(conf.Requests, conf.Window)
+	if conf.RedisURL == "" {
+		return memory
+	}
+
+	options, err := redis.ParseURL(conf.RedisURL)
+	if err != nil {
+		logger.Error("Invalid REDIS_URL, using in-memory rate limiting", zap.Error(err))
+		return memory
+	}
+
+	redisLimiter := NewRedisRateLimiter(redis.NewClient(options), conf.Requests, conf.Window)
+	return NewFallbackRateLimiter(redisLimiter, memory)
+}
+
+// DefaultLogMaxBodyBytes is the logged body size limit when none is configured
+const DefaultLogMaxBodyBytes = 1024 * 10 // 10KB
+
+// RequestLogger logs request and response details.
+// Requests slower than the configured threshold additionally emit a slow request warning.
+func RequestLogger(conf *config.LoggingConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
@@ -42,6 +113,14 @@ func RequestLogger() gin.HandlerFunc {
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 		}
 
+		// Collect the SQL executed by this request
+		var sqlCollector *logger.SQLCollector
+		if conf.LogSQL {
+			var ctx context.Context
+			ctx, sqlCollector = logger.ContextWithSQLCollector(c.Request.Context())
+			c.Request = c.Request.WithContext(ctx)
+		}
+
 		// Capture the response
 		responseWriter := &responseWriter{
 			ResponseWriter: c.Writer,
@@ -60,9 +139,13 @@ func RequestLogger() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 		userAgent := c.Request.UserAgent()
+		responseBytes := responseWriter.body.Len()
 
 		// Truncate large request/response bodies to prevent logging too much data
-		const maxBodySize = 1024 * 10 // 10KB
+		maxBodySize := conf.MaxBodyBytes
+		if maxBodySize <= 0 {
+			maxBodySize = DefaultLogMaxBodyBytes
+		}
 		truncateBody := func(body []byte) string {
 			if len(body) > maxBodySize {
 				return string(body[:maxBodySize]) + "...(truncated)"
@@ -84,6 +167,7 @@ func RequestLogger() gin.HandlerFunc {
 
 		// Create structured log
 		fields := []zap.Field{
+			zap.String("request_id", GetRequestID(c)),
 			zap.String("client_ip", clientIP),
 			zap.String("method", method),
 			zap.String("path", path),
@@ -91,6 +175,7 @@ func RequestLogger() gin.HandlerFunc {
 			zap.Int("status", status),
 			zap.Duration("duration", duration),
 			zap.String("user_agent", userAgent),
+			zap.Int("response_bytes", responseBytes),
 		}
 
 		// Only add request/response body for appropriate content types
@@ -106,8 +191,29 @@ func RequestLogger() gin.HandlerFunc {
 			}
 		}
 
+		// Add executed SQL
+		if sqlCollector != nil {
+			queries, dropped := sqlCollector.Queries()
+			fields = append(fields, zap.Strings("sql", queries))
+			if dropped > 0 {
+				fields = append(fields, zap.Int("sql_dropped", dropped))
+			}
+		}
+
+		// Record the response size
+		observeResponseSize(c, responseBytes)
+
 		// Log with appropriate level
 		logger.GetLogger().Log(logLevel, "HTTP Request", fields...)
+
+		// Flag latency outliers separately
+		if conf.SlowRequestThreshold > 0 && duration > conf.SlowRequestThreshold {
+			logger.Warn("Slow request",
+				zap.String("method", method),
+				zap.String("route", c.FullPath()),
+				zap.Duration("duration", duration),
+				zap.Duration("threshold", conf.SlowRequestThreshold))
+		}
 	}
 }
 
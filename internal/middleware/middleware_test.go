@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/ladderseeker/gin-crud-starter/internal/database"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// observeLogs replaces the global logger with one that records entries
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
+	return logs
+}
+
+func TestRequestLoggerSlowRequest(t *testing.T) {
+	logs := observeLogs(t)
+
+	// Create router with a slow and a fast handler
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger(&config.LoggingConfig{SlowRequestThreshold: 10 * time.Millisecond}))
+	router.GET("/slow/:id", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Fast request logs only the access line
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, 0, logs.FilterMessage("Slow request").Len())
+	assert.Equal(t, zapcore.InfoLevel, logs.FilterMessage("HTTP Request").All()[0].Level)
+
+	// Slow request also emits the warning
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+	slow := logs.FilterMessage("Slow request").All()
+	assert.Len(t, slow, 1)
+	assert.Equal(t, zapcore.WarnLevel, slow[0].Level)
+	assert.Equal(t, "/slow/:id", slow[0].ContextMap()["route"])
+
+	// The access line keeps its status-derived level
+	assert.Equal(t, zapcore.InfoLevel, logs.FilterMessage("HTTP Request").All()[1].Level)
+}
+
+func TestRequestLoggerSQL(t *testing.T) {
+	// Create database with SQL capture enabled
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&model.User{}))
+	assert.NoError(t, database.EnableSQLCapture(db))
+
+	newRouter := func(logSQL bool) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(RequestLogger(&config.LoggingConfig{LogSQL: logSQL}))
+		router.GET("/users", func(c *gin.Context) {
+			var users []model.User
+			db.WithContext(c.Request.Context()).Find(&users)
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		logs := observeLogs(t)
+		newRouter(true).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		entries := logs.FilterMessage("HTTP Request").All()
+		assert.Len(t, entries, 1)
+		queries, ok := entries[0].ContextMap()["sql"].([]interface{})
+		assert.True(t, ok)
+		assert.Len(t, queries, 1)
+		assert.Contains(t, queries[0], "SELECT * FROM `users`")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		logs := observeLogs(t)
+		newRouter(false).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		entries := logs.FilterMessage("HTTP Request").All()
+		assert.Len(t, entries, 1)
+		assert.NotContains(t, entries[0].ContextMap(), "sql")
+	})
+}
+
+func TestRequestLoggerBodyTruncation(t *testing.T) {
+	logs := observeLogs(t)
+
+	// Create router echoing the request body with a small body limit
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger(&config.LoggingConfig{MaxBodyBytes: 16}))
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+
+	// Send a body one byte over the limit and one exactly at it
+	send := func(body string) map[string]interface{} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body)))
+		entries := logs.FilterMessage("HTTP Request").All()
+		return entries[len(entries)-1].ContextMap()
+	}
+
+	over := send("0123456789abcdefX")
+	assert.Equal(t, "0123456789abcdef...(truncated)", over["request_body"])
+	assert.Equal(t, "0123456789abcdef...(truncated)", over["response_body"])
+
+	exact := send("0123456789abcdef")
+	assert.Equal(t, "0123456789abcdef", exact["request_body"])
+	assert.Equal(t, "0123456789abcdef", exact["response_body"])
+}
+
+func TestRequestLoggerResponseBytes(t *testing.T) {
+	logs := observeLogs(t)
+
+	// Create router returning a known body
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLogger(&config.LoggingConfig{}))
+	router.GET("/sized", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello, world")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sized", nil))
+
+	// Check the logged size matches the body
+	entries := logs.FilterMessage("HTTP Request").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, int64(len("hello, world")), entries[0].ContextMap()["response_bytes"])
+
+	// Check the histogram observed it under the route
+	var metric dto.Metric
+	assert.NoError(t, responseSize.WithLabelValues(http.MethodGet, "/sized").(prometheus.Histogram).Write(&metric))
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(len("hello, world")), metric.GetHistogram().GetSampleSum())
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/ttlstore"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Rate limit response headers
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimitResult describes the state of a client's budget after a request
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Time
+
+	// RetryAfter is how long until the budget frees up, set when the request is denied
+	RetryAfter time.Duration
+}
+
+// RateLimiter tracks request budgets per client key
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (RateLimitResult, error)
+}
+
+// RateLimit limits requests per client IP and reports the budget in response headers
+func RateLimit(limiter RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			// Fail open so a limiter outage doesn't take the API down
+			logger.Error("Rate limiter failed", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		// Report budget on every response
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Header(HeaderRateLimitReset, strconv.FormatInt(result.Reset.Unix(), 10))
+
+		if !result.Allowed {
+			appErr := apperrors.NewRateLimitedError("Too many requests", nil).WithRetryAfter(result.RetryAfter)
+			response.SetRetryAfter(c.Writer.Header(), appErr)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, appErr)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// MemoryRateLimiter is a fixed-window rate limiter kept in process memory
+type MemoryRateLimiter struct {
+	limit   int
+	window  time.Duration
+	windows *ttlstore.Store[rateWindow]
+	now     func() time.Time
+}
+
+type rateWindow struct {
+	count int
+	reset time.Time
+}
+
+// NewMemoryRateLimiter creates a limiter allowing limit requests per window per key
+func NewMemoryRateLimiter(limit int, window time.Duration) *MemoryRateLimiter {
+	l := &MemoryRateLimiter{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}
+	// Windows expire from the store as they end, so idle clients don't accumulate
+	l.windows = ttlstore.New[rateWindow](window, ttlstore.WithClock(func() time.Time { return l.now() }))
+	return l
+}
+
+// Allow consumes one request from the key's budget
+func (l *MemoryRateLimiter) Allow(_ context.Context, key string) (RateLimitResult, error) {
+	now := l.now()
+
+	allowed := false
+	w := l.windows.Update(key, func(w rateWindow, ok bool) rateWindow {
+		// Start a new window if none is active
+		if !ok {
+			w = rateWindow{reset: now.Add(l.window)}
+		}
+		if w.count < l.limit {
+			w.count++
+			allowed = true
+		}
+		return w
+	})
+
+	result := RateLimitResult{
+		Limit: l.limit,
+		Reset: w.reset,
+	}
+
+	if !allowed {
+		result.RetryAfter = w.reset.Sub(now)
+		return result, nil
+	}
+
+	result.Allowed = true
+	result.Remaining = l.limit - w.count
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRateLimitedRouter(limiter RateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(limiter))
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+	return router
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	// Create limiter with a controllable clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+	router := newRateLimitedRouter(limiter)
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w
+	}
+	reset := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+
+	// Remaining decrements across successive requests
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "1", w.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, reset, w.Header().Get(HeaderRateLimitReset))
+
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get(HeaderRateLimitRemaining))
+
+	// Budget exhausted, retry once the window ends
+	now = now.Add(15 * time.Second)
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, reset, w.Header().Get(HeaderRateLimitReset))
+	assert.Equal(t, "45", w.Header().Get("Retry-After"))
+
+	// Budget resets after the window
+	now = now.Add(45 * time.Second)
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, strconv.FormatInt(now.Add(time.Minute).Unix(), 10), w.Header().Get(HeaderRateLimitReset))
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	limiter := NewMemoryRateLimiter(1, time.Minute)
+	router := newRateLimitedRouter(limiter)
+
+	// Exhaust the first client's budget
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+	}
+
+	// Another client is unaffected
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
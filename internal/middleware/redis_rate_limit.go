@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// slidingWindowScript records a request in a sorted set of recent request times.
+// It returns whether the request is allowed, the count in the window and the reset time in milliseconds.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = now + window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window
+end
+return {allowed, count, reset}
+`)
+
+// redisKeyPrefix namespaces rate limit keys in Redis
+const redisKeyPrefix = "ratelimit:"
+
+// RedisRateLimiter is a sliding-window rate limiter shared by every instance using the same Redis
+type RedisRateLimiter struct {
+	client redis.Scripter
+	limit  int
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewRedisRateLimiter creates a limiter allowing limit requests in any window per key
+func NewRedisRateLimiter(client redis.Scripter, limit int, window time.Duration) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Allow consumes one request from the key's budget
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	member, err := requestMember()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	now := l.now().UnixMilli()
+	values, err := slidingWindowScript.Run(ctx, l.client, []string{redisKeyPrefix + key},
+		now, l.window.Milliseconds(), l.limit, member).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 3 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	result := RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     l.limit,
+		Remaining: max(l.limit-int(values[1]), 0),
+		Reset:     time.UnixMilli(values[2]),
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration(values[2]-now) * time.Millisecond
+	}
+	return result, nil
+}
+
+// requestMember returns a unique sorted set member for one request
+func requestMember() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate rate limit member: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// FallbackRateLimiter uses a secondary limiter whenever the primary one fails
+type FallbackRateLimiter struct {
+	primary  RateLimiter
+	fallback RateLimiter
+}
+
+// NewFallbackRateLimiter creates a limiter that falls back to fallback when primary returns an error
+func NewFallbackRateLimiter(primary, fallback RateLimiter) *FallbackRateLimiter {
+	return &FallbackRateLimiter{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Allow consumes one request from the primary limiter, or the fallback if the primary is unavailable
+func (l *FallbackRateLimiter) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	result, err := l.primary.Allow(ctx, key)
+	if err == nil {
+		return result, nil
+	}
+
+	logger.Warn("Rate limiter unavailable, using fallback", zap.Error(err))
+	return l.fallback.Allow(ctx, key)
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func TestRedisRateLimiterSharedAcrossInstances(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	// Two instances share the same Redis and a controllable clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := NewRedisRateLimiter(client, 3, time.Minute)
+	second := NewRedisRateLimiter(client, 3, time.Minute)
+	first.now = func() time.Time { return now }
+	second.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Requests through either instance consume the same budget
+	result, err := first.Allow(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, now.Add(time.Minute).UnixMilli(), result.Reset.UnixMilli())
+
+	now = now.Add(10 * time.Second)
+	result, err = second.Allow(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, err = first.Allow(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Budget exhausted for both instances
+	result, err = second.Allow(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Other keys are unaffected
+	result, err = second.Allow(ctx, "10.0.0.2")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// The first request slides out of the window
+	now = now.Add(50 * time.Second)
+	result, err = first.Allow(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestRedisRateLimiterMiddleware(t *testing.T) {
+	_, client := newTestRedis(t)
+	router := newRateLimitedRouter(NewRedisRateLimiter(client, 1, time.Minute))
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	w := request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "0", w.Header().Get(HeaderRateLimitRemaining))
+}
+
+func TestFallbackRateLimiterRedisUnavailable(t *testing.T) {
+	server, client := newTestRedis(t)
+	limiter := NewFallbackRateLimiter(
+		NewRedisRateLimiter(client, 5, time.Minute),
+		NewMemoryRateLimiter(1, time.Minute),
+	)
+
+	// Take Redis down
+	server.Close()
+
+	// The in-memory limiter enforces its own budget
+	result, err := limiter.Allow(context.Background(), "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Limit)
+
+	result, err = limiter.Allow(context.Background(), "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "request_id"
+
+// DefaultRequestIDHeader is used when no header name is configured
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestID reads the request ID from the given header, generating one if it is absent or invalid,
+// and echoes it back in the same header
+func RequestID(header string) gin.HandlerFunc {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	return func(c *gin.Context) {
+		id := c.GetHeader(header)
+		if !isValidRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(header, id)
+
+		// Tag every log line written with the request context
+		c.Request = c.Request.WithContext(logger.ContextWithFields(c.Request.Context(), zap.String(RequestIDKey, id)))
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the request ID of the current request
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// isValidRequestID accepts non-empty printable ASCII IDs of bounded length
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
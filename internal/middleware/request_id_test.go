@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestIDRouter(header string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(header))
+	router.Use(RequestLogger(&config.LoggingConfig{}))
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, GetRequestID(c))
+	})
+	return router
+}
+
+func TestRequestIDCustomHeader(t *testing.T) {
+	logs := observeLogs(t)
+	router := newRequestIDRouter("X-Correlation-ID")
+
+	// Send a correlation ID
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Correlation-ID", "corr-123")
+	router.ServeHTTP(w, req)
+
+	// Assert it round-trips and is logged under the stable key
+	assert.Equal(t, "corr-123", w.Header().Get("X-Correlation-ID"))
+	assert.Empty(t, w.Header().Get("X-Request-ID"))
+	assert.Equal(t, "corr-123", w.Body.String())
+	assert.Equal(t, "corr-123", logs.FilterMessage("HTTP Request").All()[0].ContextMap()["request_id"])
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	router := newRequestIDRouter("")
+
+	// Send no ID
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	// Assert one was generated under the default header
+	id := w.Header().Get(DefaultRequestIDHeader)
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, w.Body.String())
+}
+
+func TestRequestIDInvalidReplaced(t *testing.T) {
+	router := newRequestIDRouter("X-Request-ID")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("X-Request-ID", "has spaces")
+	router.ServeHTTP(w, req)
+
+	assert.NotEqual(t, "has spaces", w.Header().Get("X-Request-ID"))
+}
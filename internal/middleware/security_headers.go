@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Security header names
+const (
+	HeaderContentTypeOptions      = "X-Content-Type-Options"
+	HeaderFrameOptions            = "X-Frame-Options"
+	HeaderReferrerPolicy          = "Referrer-Policy"
+	HeaderStrictTransportSecurity = "Strict-Transport-Security"
+)
+
+// SecurityHeaders sets the configured browser security headers on every response
+func SecurityHeaders(conf config.SecurityHeadersConfig) gin.HandlerFunc {
+	// Build the header set once
+	headers := map[string]string{}
+	if conf.NoSniff {
+		headers[HeaderContentTypeOptions] = "nosniff"
+	}
+	if conf.FrameOptions != "" {
+		headers[HeaderFrameOptions] = conf.FrameOptions
+	}
+	if conf.ReferrerPolicy != "" {
+		headers[HeaderReferrerPolicy] = conf.ReferrerPolicy
+	}
+	if conf.HSTSMaxAge > 0 {
+		headers[HeaderStrictTransportSecurity] = "max-age=" + strconv.FormatInt(int64(conf.HSTSMaxAge.Seconds()), 10) + "; includeSubDomains"
+	}
+
+	return func(c *gin.Context) {
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+		c.Next()
+	}
+}
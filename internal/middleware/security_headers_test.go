@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	defaults := config.SecurityHeadersConfig{
+		Enabled:        true,
+		NoSniff:        true,
+		FrameOptions:   "DENY",
+		ReferrerPolicy: "strict-origin-when-cross-origin",
+	}
+	withHSTS := defaults
+	withHSTS.HSTSMaxAge = 365 * 24 * time.Hour
+
+	testCases := []struct {
+		name     string
+		conf     config.SecurityHeadersConfig
+		expected map[string]string
+	}{
+		{
+			name: "Defaults",
+			conf: defaults,
+			expected: map[string]string{
+				HeaderContentTypeOptions:      "nosniff",
+				HeaderFrameOptions:            "DENY",
+				HeaderReferrerPolicy:          "strict-origin-when-cross-origin",
+				HeaderStrictTransportSecurity: "",
+			},
+		},
+		{
+			name: "HSTS",
+			conf: withHSTS,
+			expected: map[string]string{
+				HeaderContentTypeOptions:      "nosniff",
+				HeaderStrictTransportSecurity: "max-age=31536000; includeSubDomains",
+			},
+		},
+		{
+			name: "Disabled",
+			conf: config.SecurityHeadersConfig{Enabled: true},
+			expected: map[string]string{
+				HeaderContentTypeOptions:      "",
+				HeaderFrameOptions:            "",
+				HeaderReferrerPolicy:          "",
+				HeaderStrictTransportSecurity: "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(SecurityHeaders(tc.conf))
+			router.GET("/ping", func(c *gin.Context) {
+				c.String(http.StatusOK, "pong")
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			for name, value := range tc.expected {
+				assert.Equal(t, value, w.Header().Get(name), name)
+			}
+		})
+	}
+}
+
+func TestSetupMiddlewareSecurityHeaders(t *testing.T) {
+	newRouter := func(conf *config.Config) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		SetupMiddleware(router, conf)
+		router.GET("/ping", func(c *gin.Context) {
+			c.String(http.StatusOK, "pong")
+		})
+		return router
+	}
+	headers := config.SecurityHeadersConfig{Enabled: true, NoSniff: true, HSTSMaxAge: time.Hour}
+
+	// HSTS is dropped without TLS
+	w := httptest.NewRecorder()
+	newRouter(&config.Config{Server: config.ServerConfig{SecurityHeaders: headers}}).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, "nosniff", w.Header().Get(HeaderContentTypeOptions))
+	assert.Empty(t, w.Header().Get(HeaderStrictTransportSecurity))
+
+	// And sent with it
+	tls := config.TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}
+	w = httptest.NewRecorder()
+	newRouter(&config.Config{Server: config.ServerConfig{SecurityHeaders: headers, TLS: tls}}).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, "max-age=3600; includeSubDomains", w.Header().Get(HeaderStrictTransportSecurity))
+
+	// Disabling the middleware omits every header
+	headers.Enabled = false
+	w = httptest.NewRecorder()
+	newRouter(&config.Config{Server: config.ServerConfig{SecurityHeaders: headers, TLS: tls}}).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Empty(t, w.Header().Get(HeaderContentTypeOptions))
+	assert.Empty(t, w.Header().Get(HeaderStrictTransportSecurity))
+}
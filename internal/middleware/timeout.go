@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/ladderseeker/gin-crud-starter/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Timeout responds with 503 and a JSON error when a handler runs longer than timeout.
+// The handler's context is cancelled at the deadline and anything it writes afterwards is discarded.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		// Buffer the response so it can be dropped on timeout
+		original := c.Writer
+		writer := newTimeoutWriter(original)
+		c.Writer = writer
+
+		done := make(chan struct{})
+		var recovered any
+		go func() {
+			defer close(done)
+			defer func() { recovered = recover() }()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+			writer.flushTo(original)
+		case <-ctx.Done():
+			writer.markTimedOut()
+			logger.Warn("Request timed out",
+				zap.String("request_id", GetRequestID(c)),
+				zap.Duration("timeout", timeout),
+			)
+			writeTimeoutResponse(original)
+
+			// Keep the context alive until the handler stops using it
+			<-done
+		}
+
+		c.Writer = original
+		if recovered != nil {
+			panic(recovered)
+		}
+	}
+}
+
+// writeTimeoutResponse writes and flushes a complete 503 response
+func writeTimeoutResponse(w gin.ResponseWriter) {
+	appErr := apperrors.NewTimeoutError("The request took too long to process", nil)
+	body, _ := json.Marshal(appErr)
+	response.SetRetryAfter(w.Header(), appErr)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write(body)
+	w.Flush()
+}
+
+// timeoutWriter buffers a handler's response until it completes
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	size     int
+	timedOut bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: w,
+		header:         make(http.Header),
+		status:         http.StatusOK,
+		size:           -1,
+	}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code > 0 && w.size == -1 {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size == -1 {
+		w.size = 0
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.size == -1 {
+		w.size = 0
+	}
+	n, err := w.body.Write(data)
+	w.size += n
+	return n, err
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+func (w *timeoutWriter) Written() bool {
+	return w.Size() != -1
+}
+
+// Flush is a no-op, the response is sent once the handler completes
+func (w *timeoutWriter) Flush() {}
+
+func (w *timeoutWriter) markTimedOut() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+}
+
+// flushTo copies the buffered response to the underlying writer
+func (w *timeoutWriter) flushTo(dst gin.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	dst.WriteHeader(w.status)
+	if w.size != -1 {
+		dst.WriteHeaderNow()
+		_, _ = dst.Write(w.body.Bytes())
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTimeoutServer starts a server whose write timeout is shorter than the slow handler
+func newTimeoutServer(t *testing.T, withMiddleware bool) *httptest.Server {
+	serverConfig := config.ServerConfig{WriteTimeout: 200 * time.Millisecond, ResponseTimeout: time.Second}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if withMiddleware {
+		router.Use(Timeout(serverConfig.HandlerTimeout()))
+	}
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(400 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "fast")
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = serverConfig.WriteTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTimeoutReturnsJSONBeforeWriteTimeout(t *testing.T) {
+	server := newTimeoutServer(t, true)
+
+	// Call the slow handler
+	resp, err := http.Get(server.URL + "/slow")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+
+	// Assert a clean JSON timeout error
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.JSONEq(t, `{"code":"TIMEOUT","message":"The request took too long to process","retryable":true}`, string(body))
+}
+
+func TestTimeoutWithoutMiddlewareDropsConnection(t *testing.T) {
+	server := newTimeoutServer(t, false)
+
+	// Call the slow handler, the write deadline has passed by the time it responds
+	resp, err := http.Get(server.URL + "/slow")
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	assert.Error(t, err)
+}
+
+func TestTimeoutPassesFastResponsesThrough(t *testing.T) {
+	server := newTimeoutServer(t, true)
+
+	resp, err := http.Get(server.URL + "/fast")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "fast", resp.Header.Get("X-Handler"))
+	assert.JSONEq(t, `{"status":"ok"}`, string(body))
+}
+
+func TestSetupMiddlewareTimeoutIsOptIn(t *testing.T) {
+	testCases := []struct {
+		name       string
+		server     config.ServerConfig
+		expectWrap bool
+	}{
+		{name: "Default", server: config.ServerConfig{WriteTimeout: 10 * time.Second}},
+		{name: "Configured", server: config.ServerConfig{WriteTimeout: 10 * time.Second, ResponseTimeout: 5 * time.Second}, expectWrap: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			SetupMiddleware(router, &config.Config{Server: tc.server})
+
+			// Inspect the writer and deadline the handler sees
+			var wrapped, hasDeadline bool
+			router.GET("/ping", func(c *gin.Context) {
+				_, wrapped = c.Writer.(*timeoutWriter)
+				_, hasDeadline = c.Request.Context().Deadline()
+				c.String(http.StatusOK, "pong")
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expectWrap, wrapped)
+			assert.Equal(t, tc.expectWrap, hasDeadline)
+		})
+	}
+}
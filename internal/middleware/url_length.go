@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxURLLength is the URL length limit when none is configured
+const DefaultMaxURLLength = 8192
+
+// MaxURLLength rejects requests whose path and query together exceed n bytes with 414.
+// Register it before other middleware so oversized URLs are never logged or routed.
+func MaxURLLength(n int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := c.Request.RequestURI
+		if uri == "" {
+			uri = c.Request.URL.RequestURI()
+		}
+
+		if len(uri) > n {
+			c.AbortWithStatusJSON(http.StatusRequestURITooLong, apperrors.New(
+				http.StatusRequestURITooLong,
+				apperrors.ErrCodeURITooLong,
+				"URL too long",
+				map[string]interface{}{"max_length": n},
+				nil,
+			))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxURLLength(t *testing.T) {
+	// Create router limiting URLs to 32 bytes
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MaxURLLength(32))
+	router.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, "pong")
+	})
+
+	testCases := []struct {
+		name           string
+		url            string
+		expectedStatus int
+	}{
+		{name: "Normal", url: "/ping?q=short", expectedStatus: http.StatusOK},
+		{name: "AtLimit", url: "/ping?q=" + strings.Repeat("a", 24), expectedStatus: http.StatusOK},
+		{name: "LongQuery", url: "/ping?q=" + strings.Repeat("a", 25), expectedStatus: http.StatusRequestURITooLong},
+		{name: "LongUnknownPath", url: "/" + strings.Repeat("a", 40), expectedStatus: http.StatusRequestURITooLong},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusRequestURITooLong {
+				assert.Contains(t, w.Body.String(), `"code":"URI_TOO_LONG"`)
+			}
+		})
+	}
+}
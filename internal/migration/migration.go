@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+var (
+	mu       sync.Mutex
+	entities []interface{}
+	hooks    []Hook
+)
+
+// Hook adjusts the schema after all entities are migrated, for constraints AutoMigrate can't express
+type Hook func(db *gorm.DB) error
+
+// Register adds entities to the set migrated by AutoMigrate.
+// Models call it from init so every bootstrap migrates the same schemas.
+func Register(models ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, model := range models {
+		if !isRegistered(model) {
+			entities = append(entities, model)
+		}
+	}
+}
+
+// AfterMigrate adds a hook run by AutoMigrate once every entity is migrated.
+// Hooks run in registration order and must be idempotent.
+func AfterMigrate(hook Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	hooks = append(hooks, hook)
+}
+
+// Entities returns the registered entities in registration order
+func Entities() []interface{} {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]interface{}(nil), entities...)
+}
+
+// AutoMigrate migrates all registered entities, then runs the registered hooks
+func AutoMigrate(db *gorm.DB) error {
+	for _, entity := range Entities() {
+		if err := db.AutoMigrate(entity); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	registered := append([]Hook(nil), hooks...)
+	mu.Unlock()
+
+	for _, hook := range registered {
+		if err := hook(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isRegistered checks if an entity of the same type is already registered
+func isRegistered(model interface{}) bool {
+	modelType := reflect.TypeOf(model)
+	for _, entity := range entities {
+		if reflect.TypeOf(entity) == modelType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// widget is a model only known to this test
+type widget struct {
+	ID   uint `gorm:"primaryKey"`
+	Name string
+}
+
+func TestRegisterIncludesModelInMigration(t *testing.T) {
+	// Register the model twice
+	Register(&widget{})
+	Register(&widget{})
+
+	// Assert it is in the migrated set exactly once
+	count := 0
+	for _, entity := range Entities() {
+		if _, ok := entity.(*widget); ok {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+
+	// Assert migrating creates its table
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, AutoMigrate(db))
+	assert.True(t, db.Migrator().HasTable(&widget{}))
+}
+
+func TestAfterMigrateRunsHooks(t *testing.T) {
+	// Register a hook that depends on a migrated table
+	Register(&widget{})
+	AfterMigrate(func(db *gorm.DB) error {
+		return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_widgets_name_lower ON widgets (LOWER(name))").Error
+	})
+
+	// Migrate twice to check the hook is idempotent
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	assert.NoError(t, err)
+	assert.NoError(t, AutoMigrate(db))
+	assert.NoError(t, AutoMigrate(db))
+
+	// Assert the hook's index exists
+	assert.True(t, db.Migrator().HasIndex(&widget{}, "idx_widgets_name_lower"))
+}
@@ -0,0 +1,43 @@
+package model
+
+// BulkFailure is an item of a bulk operation that exists but couldn't be processed
+type BulkFailure struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// BulkResult reports the per-item outcome of a bulk operation, so clients handle partial success uniformly.
+// Each distinct requested ID appears in exactly one list and Total counts them.
+type BulkResult struct {
+	Succeeded []uint        `json:"succeeded"`
+	Failed    []BulkFailure `json:"failed"`
+	NotFound  []uint        `json:"not_found"`
+	Total     int           `json:"total"`
+}
+
+// NewBulkResult returns an empty result whose lists serialize as [] rather than null
+func NewBulkResult() *BulkResult {
+	return &BulkResult{
+		Succeeded: []uint{},
+		Failed:    []BulkFailure{},
+		NotFound:  []uint{},
+	}
+}
+
+// Succeed records id as processed
+func (r *BulkResult) Succeed(id uint) {
+	r.Succeeded = append(r.Succeeded, id)
+	r.Total++
+}
+
+// Fail records id as existing but not processed, for reason
+func (r *BulkResult) Fail(id uint, reason string) {
+	r.Failed = append(r.Failed, BulkFailure{ID: id, Reason: reason})
+	r.Total++
+}
+
+// Missing records id as not found
+func (r *BulkResult) Missing(id uint) {
+	r.NotFound = append(r.NotFound, id)
+	r.Total++
+}
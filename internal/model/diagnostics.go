@@ -0,0 +1,42 @@
+package model
+
+// Diagnostics is a snapshot of the server runtime for debugging without shell access.
+// It holds counters only, never configuration, connection strings or request data.
+type Diagnostics struct {
+	GoVersion  string               `json:"go_version"`
+	NumCPU     int                  `json:"num_cpu"`
+	Goroutines int                  `json:"goroutines"`
+	Memory     MemoryDiagnostics    `json:"memory"`
+	GC         GCDiagnostics        `json:"gc"`
+	Database   *DatabaseDiagnostics `json:"database,omitempty"`
+}
+
+// MemoryDiagnostics is the subset of runtime.MemStats useful to spot leaks and heap growth
+type MemoryDiagnostics struct {
+	AllocBytes      uint64 `json:"alloc_bytes"`
+	TotalAllocBytes uint64 `json:"total_alloc_bytes"`
+	SysBytes        uint64 `json:"sys_bytes"`
+	HeapAllocBytes  uint64 `json:"heap_alloc_bytes"`
+	HeapInuseBytes  uint64 `json:"heap_inuse_bytes"`
+	HeapObjects     uint64 `json:"heap_objects"`
+	Mallocs         uint64 `json:"mallocs"`
+	Frees           uint64 `json:"frees"`
+}
+
+// GCDiagnostics summarizes garbage collector activity since the process started
+type GCDiagnostics struct {
+	NumGC        int64      `json:"num_gc"`
+	LastGC       *Timestamp `json:"last_gc,omitempty"`
+	PauseTotalMs float64    `json:"pause_total_ms"`
+	NextGCBytes  uint64     `json:"next_gc_bytes"`
+}
+
+// DatabaseDiagnostics mirrors the connection pool counters of sql.DBStats
+type DatabaseDiagnostics struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     float64 `json:"wait_duration_ms"`
+}
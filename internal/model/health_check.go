@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+)
+
+// HealthCheck is written and rolled back by the deep readiness check, the table stays empty
+type HealthCheck struct {
+	ID        uint      `gorm:"primaryKey"`
+	CheckedAt time.Time `gorm:"not null"`
+}
+
+func (*HealthCheck) TableName() string {
+	return "health_checks"
+}
+
+func init() {
+	migration.Register(&HealthCheck{})
+}
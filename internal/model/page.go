@@ -0,0 +1,10 @@
+package model
+
+// MaxPageSize bounds the number of rows a single page may request
+const MaxPageSize = 100
+
+// Page bounds a listing query, a zero Limit returns every row
+type Page struct {
+	Offset int
+	Limit  int
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+)
+
+// Default roles available in every installation
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Role represents a role that can be assigned to users
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:20;uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func init() {
+	migration.Register(&Role{})
+}
+
+func (*Role) TableName() string {
+	return "roles"
+}
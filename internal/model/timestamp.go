@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// timestampZone is the zone timestamps are serialized in
+var timestampZone = time.UTC
+
+// SetTimestampZone sets the zone timestamps are serialized in, nil restores UTC
+func SetTimestampZone(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	timestampZone = loc
+}
+
+// Timestamp wraps time.Time so that it always serializes as RFC3339 in the configured zone, UTC by default
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp creates a new Timestamp from a time.Time
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// MarshalJSON encodes the timestamp as an RFC3339 string in the configured zone
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.In(timestampZone).Format(time.RFC3339))
+}
+
+// UnmarshalJSON decodes an RFC3339 string into the timestamp
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed.UTC()
+	return nil
+}
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +21,31 @@ type User struct {
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// emailLowerIndex enforces case-insensitive email uniqueness, whatever the write path
+const emailLowerIndex = "idx_users_email_lower"
+
+func init() {
+	migration.Register(&User{})
+	migration.AfterMigrate(createEmailLowerIndex)
+}
+
+// createEmailLowerIndex adds a unique index on the lower-cased email, in the dialect of db
+func createEmailLowerIndex(db *gorm.DB) error {
+	var statement string
+	switch db.Dialector.Name() {
+	case "postgres", "sqlite":
+		statement = fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON users (LOWER(email))", emailLowerIndex)
+	case "mysql":
+		if db.Migrator().HasIndex(&User{}, emailLowerIndex) {
+			return nil
+		}
+		statement = fmt.Sprintf("CREATE UNIQUE INDEX %s ON users ((LOWER(email)))", emailLowerIndex)
+	default:
+		return fmt.Errorf("case-insensitive email index is not supported by the %s dialect", db.Dialector.Name())
+	}
+	return db.Exec(statement).Error
+}
+
 func (*User) TableName() string {
 	return "users"
 }
@@ -27,25 +54,59 @@ type UserCreate struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"omitempty,oneof=admin user"`
 }
 
 type UserUpdate struct {
 	Name     *string `json:"name" binding:"omitempty"`
 	Email    *string `json:"email" binding:"omitempty,email"`
 	Password *string `json:"password" binding:"omitempty,min=6"`
-	Role     *string `json:"role" binding:"omitempty,oneof=admin user"`
 	Active   *bool   `json:"active" binding:"omitempty"`
 }
 
+type UserRoleChange struct {
+	Role string `json:"role" binding:"required,max=20"`
+}
+
+type UserBulkStatusUpdate struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1,max=1000,dive,gt=0"`
+	Active  *bool  `json:"active" binding:"required"`
+}
+
+// MaxEmailLookup bounds the number of emails resolved in one lookup
+const MaxEmailLookup = 100
+
+type UserEmailLookup struct {
+	Emails []string `json:"emails" binding:"required,min=1,max=100,dive,required,email"`
+}
+
+type UserEmailLookupResult struct {
+	Users    []UserResponse `json:"users"`
+	NotFound []string       `json:"not_found"`
+}
+
+// UserRoleStatusCount is the number of users sharing a role and active status
+type UserRoleStatusCount struct {
+	Role   string
+	Active bool
+	Count  int64
+}
+
+type UserSummary struct {
+	Total    int64            `json:"total"`
+	Active   int64            `json:"active"`
+	Inactive int64            `json:"inactive"`
+	ByRole   map[string]int64 `json:"by_role"`
+}
+
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	Active    bool      `json:"active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	Active    bool       `json:"active"`
+	CreatedAt Timestamp  `json:"created_at"`
+	UpdatedAt Timestamp  `json:"updated_at"`
+	DeletedAt *Timestamp `json:"deleted_at,omitempty"`
 }
 
 func (u *User) ToResponse() UserResponse {
@@ -55,7 +116,17 @@ func (u *User) ToResponse() UserResponse {
 		Email:     u.Email,
 		Role:      u.Role,
 		Active:    u.Active,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		CreatedAt: NewTimestamp(u.CreatedAt),
+		UpdatedAt: NewTimestamp(u.UpdatedAt),
+		DeletedAt: deletedAt(u.DeletedAt),
+	}
+}
+
+// deletedAt returns the deletion time of a soft-deleted user, or nil
+func deletedAt(deleted gorm.DeletedAt) *Timestamp {
+	if !deleted.Valid {
+		return nil
 	}
+	timestamp := NewTimestamp(deleted.Time)
+	return &timestamp
 }
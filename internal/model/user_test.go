@@ -0,0 +1,63 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserResponseTimestampSerialization(t *testing.T) {
+	// Create a user with timestamps in a non-UTC zone
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	user := &User{
+		ID:        1,
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		CreatedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, zone),
+		UpdatedAt: time.Date(2024, 1, 3, 8, 30, 0, 0, zone),
+	}
+
+	// Serialize the response
+	data, err := json.Marshal(user.ToResponse())
+	assert.NoError(t, err)
+
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(data, &fields))
+
+	// Assert snake_case keys and UTC RFC3339 values
+	assert.Equal(t, "2024-01-02T10:00:00Z", fields["created_at"])
+	assert.Equal(t, "2024-01-03T06:30:00Z", fields["updated_at"])
+	assert.NotContains(t, fields, "createdAt")
+	assert.NotContains(t, fields, "updatedAt")
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	original := NewTimestamp(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+
+	data, err := json.Marshal(original)
+	assert.NoError(t, err)
+
+	var decoded Timestamp
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, original.Equal(decoded.Time))
+}
+
+func TestTimestampZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.NoError(t, err)
+	SetTimestampZone(tokyo)
+	t.Cleanup(func() { SetTimestampZone(nil) })
+
+	// Serialize a UTC time in the configured zone
+	data, err := json.Marshal(NewTimestamp(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-01-02T19:00:00+09:00"`, string(data))
+
+	// Parsing keeps the instant
+	var parsed Timestamp
+	assert.NoError(t, json.Unmarshal(data, &parsed))
+	assert.True(t, parsed.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// breakerState is the state of a CircuitBreaker
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker stops calling the database after repeated failures.
+// Once threshold consecutive calls fail it rejects calls for the cooldown,
+// then lets a single probe through and closes again if the probe succeeds.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a breaker opening after threshold consecutive failures for cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a call may proceed, moving an expired open breaker to half-open
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		// Let a single probe through
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// remainingCooldown is how long an open breaker keeps rejecting calls
+func (b *CircuitBreaker) remainingCooldown() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != breakerOpen {
+		return 0
+	}
+	return b.cooldown - b.now().Sub(b.openedAt)
+}
+
+// record updates the breaker with the outcome of a call
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isDatabaseFailure(err) {
+		if b.state != breakerClosed {
+			logger.Info("Database circuit breaker closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			logger.Warn("Database circuit breaker opened", zap.Int("failures", b.failures), zap.Duration("cooldown", b.cooldown))
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isDatabaseFailure distinguishes database outages from expected outcomes such as not found or duplicates.
+// Only lost connections, server shutdowns and driver or server failures count, so a client repeating a
+// rejected write can't open the breaker. Cancelled and timed out contexts are the caller giving up.
+func isDatabaseFailure(err error) bool {
+	if err == nil || stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if stderrors.Is(err, errPanicked) {
+		return true
+	}
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Code != errors.ErrCodeDatabase {
+		return false
+	}
+	return isOutage(err)
+}
+
+// errPanicked is recorded for calls that panicked
+var errPanicked = stderrors.New("database call panicked")
+
+// guard runs fn through the breaker, failing fast while it is open
+func guard[T any](b *CircuitBreaker, fn func() (T, error)) (T, error) {
+	if !b.allow() {
+		var zero T
+		return zero, errors.NewServiceUnavailableError("Database temporarily unavailable", nil).WithRetryAfter(b.remainingCooldown())
+	}
+	// Record in a defer so a panicking probe reopens the breaker instead of leaving it half-open
+	completed := false
+	defer func() {
+		if !completed {
+			b.record(errPanicked)
+		}
+	}()
+	result, err := fn()
+	completed = true
+	b.record(err)
+	return result, err
+}
+
+// guardErr runs fn through the breaker for calls returning only an error
+func guardErr(b *CircuitBreaker, fn func() error) error {
+	_, err := guard(b, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+)
+
+// circuitBreakerUserRepository guards a UserRepository with a circuit breaker
+type circuitBreakerUserRepository struct {
+	next    UserRepository
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerUserRepository wraps next so that calls fail fast while breaker is open
+func NewCircuitBreakerUserRepository(next UserRepository, breaker *CircuitBreaker) UserRepository {
+	return &circuitBreakerUserRepository{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (r *circuitBreakerUserRepository) FindAll(ctx context.Context, page model.Page) ([]model.User, error) {
+	return guard(r.breaker, func() ([]model.User, error) { return r.next.FindAll(ctx, page) })
+}
+
+func (r *circuitBreakerUserRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
+	return guard(r.breaker, func() (*model.User, error) { return r.next.FindByID(ctx, id) })
+}
+
+func (r *circuitBreakerUserRepository) FindByIDUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	return guard(r.breaker, func() (*model.User, error) { return r.next.FindByIDUnscoped(ctx, id) })
+}
+
+func (r *circuitBreakerUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return guard(r.breaker, func() (*model.User, error) { return r.next.FindByEmail(ctx, email) })
+}
+
+func (r *circuitBreakerUserRepository) FindByEmails(ctx context.Context, emails []string) ([]model.User, error) {
+	return guard(r.breaker, func() ([]model.User, error) { return r.next.FindByEmails(ctx, emails) })
+}
+
+func (r *circuitBreakerUserRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	return guard(r.breaker, func() (bool, error) { return r.next.Exists(ctx, id) })
+}
+
+func (r *circuitBreakerUserRepository) Create(ctx context.Context, user *model.User) error {
+	return guardErr(r.breaker, func() error { return r.next.Create(ctx, user) })
+}
+
+func (r *circuitBreakerUserRepository) Update(ctx context.Context, user *model.User) error {
+	return guardErr(r.breaker, func() error { return r.next.Update(ctx, user) })
+}
+
+func (r *circuitBreakerUserRepository) Delete(ctx context.Context, id uint) error {
+	return guardErr(r.breaker, func() error { return r.next.Delete(ctx, id) })
+}
+
+func (r *circuitBreakerUserRepository) LockActiveIDsInRole(ctx context.Context, role string) ([]uint, error) {
+	return guard(r.breaker, func() ([]uint, error) { return r.next.LockActiveIDsInRole(ctx, role) })
+}
+
+func (r *circuitBreakerUserRepository) CountByRoleAndStatus(ctx context.Context) ([]model.UserRoleStatusCount, error) {
+	return guard(r.breaker, func() ([]model.UserRoleStatusCount, error) { return r.next.CountByRoleAndStatus(ctx) })
+}
+
+func (r *circuitBreakerUserRepository) UpdateActiveStatus(ctx context.Context, ids []uint, active bool) ([]uint, error) {
+	return guard(r.breaker, func() ([]uint, error) { return r.next.UpdateActiveStatus(ctx, ids, active) })
+}
+
+// circuitBreakerRoleRepository guards a RoleRepository with a circuit breaker
+type circuitBreakerRoleRepository struct {
+	next    RoleRepository
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerRoleRepository wraps next so that calls fail fast while breaker is open
+func NewCircuitBreakerRoleRepository(next RoleRepository, breaker *CircuitBreaker) RoleRepository {
+	return &circuitBreakerRoleRepository{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (r *circuitBreakerRoleRepository) Exists(ctx context.Context, name string) (bool, error) {
+	return guard(r.breaker, func() (bool, error) { return r.next.Exists(ctx, name) })
+}
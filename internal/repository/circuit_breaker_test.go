@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyRoleRepository fails with a database error while down is set and counts its calls
+type flakyRoleRepository struct {
+	down  bool
+	calls int
+}
+
+func (r *flakyRoleRepository) Exists(context.Context, string) (bool, error) {
+	r.calls++
+	if r.down {
+		return false, errors.NewDatabaseError("Failed to check role existence", driver.ErrBadConn)
+	}
+	return true, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	// Create a breaker with a controllable clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	inner := &flakyRoleRepository{down: true}
+	repo := NewCircuitBreakerRoleRepository(inner, breaker)
+	ctx := context.Background()
+
+	// Repeated failures open the breaker
+	for i := 0; i < 3; i++ {
+		_, err := repo.Exists(ctx, model.RoleUser)
+		assert.Equal(t, errors.ErrCodeDatabase, err.(*errors.AppError).Code)
+	}
+	assert.Equal(t, 3, inner.calls)
+
+	// Calls now fail fast without reaching the database
+	_, err := repo.Exists(ctx, model.RoleUser)
+	assert.Equal(t, 503, errors.GetStatusCode(err))
+	assert.Equal(t, errors.ErrCodeUnavailable, err.(*errors.AppError).Code)
+	assert.Equal(t, 30*time.Second, err.(*errors.AppError).RetryAfter)
+	assert.Equal(t, 3, inner.calls)
+
+	// Clients are told to retry once the cooldown ends
+	now = now.Add(10 * time.Second)
+	_, err = repo.Exists(ctx, model.RoleUser)
+	assert.Equal(t, 20*time.Second, err.(*errors.AppError).RetryAfter)
+	now = now.Add(-10 * time.Second)
+
+	// A failed probe after the cooldown reopens it
+	now = now.Add(30 * time.Second)
+	_, err = repo.Exists(ctx, model.RoleUser)
+	assert.Equal(t, errors.ErrCodeDatabase, err.(*errors.AppError).Code)
+	assert.Equal(t, 4, inner.calls)
+
+	_, err = repo.Exists(ctx, model.RoleUser)
+	assert.Equal(t, errors.ErrCodeUnavailable, err.(*errors.AppError).Code)
+	assert.Equal(t, 4, inner.calls)
+
+	// A successful probe after the next cooldown closes it
+	inner.down = false
+	now = now.Add(30 * time.Second)
+	exists, err := repo.Exists(ctx, model.RoleUser)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = repo.Exists(ctx, model.RoleUser)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, inner.calls)
+}
+
+func TestCircuitBreakerIgnoresExpectedErrors(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+	repo := NewCircuitBreakerUserRepository(NewUserRepository(newTestDB(t)), breaker)
+
+	// Not found results are not database failures
+	for i := 0; i < 5; i++ {
+		_, err := repo.FindByID(context.Background(), 99)
+		assert.True(t, errors.IsNotFound(err))
+	}
+	assert.True(t, breaker.allow())
+}
+
+func TestCircuitBreakerIgnoresUniqueViolations(t *testing.T) {
+	db := newTestDB(t)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	repo := NewCircuitBreakerUserRepository(NewUserRepository(db), breaker)
+	users := seedUsers(t, db,
+		model.User{Name: "User 1", Email: "user1@example.com", Password: "x"},
+		model.User{Name: "User 2", Email: "user2@example.com", Password: "x"},
+	)
+
+	// Repeatedly take another user's email
+	for i := 0; i < 5; i++ {
+		user := users[1]
+		user.Email = users[0].Email
+		err := repo.Update(context.Background(), &user)
+		assert.Equal(t, errors.ErrCodeDuplicateResource, err.(*errors.AppError).Code)
+	}
+
+	// Assert the breaker stayed closed
+	_, err := repo.FindByID(context.Background(), users[0].ID)
+	assert.NoError(t, err)
+}
+
+func TestIsDatabaseFailure(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "LostConnection", err: errors.NewDatabaseError("Failed", driver.ErrBadConn), expected: true},
+		{name: "ServerShutdown", err: errors.NewDatabaseError("Failed", &pgconn.PgError{Code: "57P01"}), expected: true},
+		{name: "TooManyConnections", err: errors.NewDatabaseError("Failed", &pgconn.PgError{Code: "53300"}), expected: true},
+		{name: "Panicked", err: errPanicked, expected: true},
+		{name: "UniqueViolation", err: errors.NewDatabaseError("Failed", &pgconn.PgError{Code: "23505"}), expected: false},
+		{name: "SyntaxError", err: errors.NewDatabaseError("Failed", &pgconn.PgError{Code: "42601"}), expected: false},
+		{name: "NoCause", err: errors.NewDatabaseError("Failed", nil), expected: false},
+		{name: "Duplicate", err: errors.NewDuplicateResourceError("Exists", nil, nil), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isDatabaseFailure(tc.err))
+		})
+	}
+}
+
+func TestCircuitBreakerIgnoresContextErrors(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+
+	// Abandoned and timed out requests, wrapped as the repositories wrap them
+	for _, cause := range []error{context.Canceled, context.DeadlineExceeded, context.Canceled} {
+		err := guardErr(breaker, func() error {
+			return errors.NewDatabaseError("Failed to retrieve user", cause)
+		})
+		assert.Equal(t, errors.ErrCodeDatabase, err.(*errors.AppError).Code)
+	}
+
+	// Assert the breaker stayed closed
+	assert.NoError(t, guardErr(breaker, func() error { return nil }))
+}
+
+func TestCircuitBreakerPanickingProbeReopens(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(1, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	// Open the breaker, then let the cooldown pass
+	_ = guardErr(breaker, func() error { return errors.NewDatabaseError("Failed", driver.ErrBadConn) })
+	now = now.Add(30 * time.Second)
+
+	// The probe panics
+	assert.Panics(t, func() {
+		_ = guardErr(breaker, func() error { panic("driver bug") })
+	})
+
+	// Assert the breaker reopened, and lets a probe through after the next cooldown
+	err := guardErr(breaker, func() error { return nil })
+	assert.Equal(t, errors.ErrCodeUnavailable, err.(*errors.AppError).Code)
+
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, guardErr(breaker, func() error { return nil }))
+}
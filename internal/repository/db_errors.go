@@ -0,0 +1,86 @@
+package repository
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+)
+
+// Postgres SQLSTATEs for constraint failures
+const (
+	pgNotNullViolation = "23502"
+	pgUniqueViolation  = "23505"
+)
+
+// sqliteNotNullPrefix starts SQLite's NOT NULL constraint failure message
+const sqliteNotNullPrefix = "NOT NULL constraint failed: "
+
+// sqliteUniqueMessage is contained in SQLite's UNIQUE constraint failure message
+const sqliteUniqueMessage = "UNIQUE constraint failed"
+
+// sqliteBusyMessages are SQLite's errors for a lock held by another connection (SQLITE_BUSY and SQLITE_LOCKED)
+var sqliteBusyMessages = []string{"database is locked", "database table is locked"}
+
+// isSQLiteBusy reports whether err is SQLite refusing a statement because another connection holds the lock.
+// The message is matched so the SQLite driver isn't linked into builds that don't use it.
+func isSQLiteBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	for _, busy := range sqliteBusyMessages {
+		if strings.Contains(message, busy) {
+			return true
+		}
+	}
+	return false
+}
+
+// notNullColumn reports the column whose NOT NULL constraint a write violated
+func notNullColumn(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		if pgErr.Code == pgNotNullViolation {
+			return pgErr.ColumnName, true
+		}
+		return "", false
+	}
+
+	// SQLite reports the violation as "NOT NULL constraint failed: table.column"
+	if _, after, ok := strings.Cut(err.Error(), sqliteNotNullPrefix); ok {
+		column := after
+		if _, name, found := strings.Cut(after, "."); found {
+			column = name
+		}
+		return column, true
+	}
+	return "", false
+}
+
+// isUniqueViolation reports whether a write failed because it duplicated a unique value
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+	return strings.Contains(err.Error(), sqliteUniqueMessage)
+}
+
+// writeError converts a failed insert or update into an AppError. Unique violations are reported as
+// duplicates, and NOT NULL violations name the column the write didn't provide a value for.
+// Neither is a database failure, so they don't count towards the circuit breaker.
+func writeError(message string, err error) *errors.AppError {
+	if isUniqueViolation(err) {
+		return errors.NewDuplicateResourceError(fmt.Sprintf("%s: a record with the same value already exists", message), nil, err)
+	}
+	if column, ok := notNullColumn(err); ok {
+		return errors.New(http.StatusInternalServerError, errors.ErrCodeInternal,
+			fmt.Sprintf("%s: column %s requires a value", message, column),
+			map[string]interface{}{"column": column}, err)
+	}
+	return errors.NewDatabaseError(message, err)
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotNullColumn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		column   string
+		expected bool
+	}{
+		{name: "Postgres", err: &pgconn.PgError{Code: "23502", ColumnName: "nickname"}, column: "nickname", expected: true},
+		{name: "PostgresOtherViolation", err: &pgconn.PgError{Code: "23505"}, expected: false},
+		{name: "SQLite", err: errors.New("NOT NULL constraint failed: users.nickname"), column: "nickname", expected: true},
+		{name: "Other", err: errors.New("connection refused"), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			column, ok := notNullColumn(tc.err)
+			assert.Equal(t, tc.expected, ok)
+			assert.Equal(t, tc.column, column)
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode string
+	}{
+		{name: "PostgresUnique", err: &pgconn.PgError{Code: "23505"}, expectedCode: apperrors.ErrCodeDuplicateResource},
+		{name: "SQLiteUnique", err: errors.New("UNIQUE constraint failed: index 'idx_users_email_lower'"), expectedCode: apperrors.ErrCodeDuplicateResource},
+		{name: "NotNull", err: &pgconn.PgError{Code: "23502", ColumnName: "nickname"}, expectedCode: apperrors.ErrCodeInternal},
+		{name: "Other", err: errors.New("connection refused"), expectedCode: apperrors.ErrCodeDatabase},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appErr := writeError("Failed to update user", tc.err)
+			assert.Equal(t, tc.expectedCode, appErr.Code)
+			assert.ErrorIs(t, appErr, tc.err)
+		})
+	}
+}
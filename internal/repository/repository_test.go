@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+	_ "github.com/ladderseeker/gin-crud-starter/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB creates an in-memory SQLite database with all schemas migrated
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get test database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := migration.AutoMigrate(db); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return db
+}
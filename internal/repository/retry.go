@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"io"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// RetryPolicy bounds how read queries are retried after transient connection errors
+type RetryPolicy struct {
+	// Retries is the number of attempts after the first, zero disables retries
+	Retries int
+	// Backoff is the delay before the first retry, growing linearly with each attempt
+	Backoff time.Duration
+}
+
+// retryRead runs the read fn, retrying it while it fails with a transient connection error.
+// Reads inside a transaction are never retried because the transaction can't survive a dropped connection.
+func retryRead[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		value, err := fn()
+		if err == nil || attempt > policy.Retries || !isTransientError(err) || inTransaction(ctx) {
+			return value, err
+		}
+
+		// Back off before trying again, giving up if the caller stops waiting
+		timer := time.NewTimer(policy.Backoff * time.Duration(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return value, err
+		}
+	}
+}
+
+// Writes refused because SQLite is locked by another connection are repeated briefly
+const (
+	busyRetries = 5
+	busyBackoff = 10 * time.Millisecond
+)
+
+// retryBusy runs the write fn, repeating it while SQLite reports the database is locked.
+// A locked database refuses the statement before it runs, so repeating it is safe as long as fn only issues
+// that statement: any caller logic in fn, such as hashing or enqueueing, runs again on every attempt.
+// Inside a transaction fn isn't repeated, the Transactor only retries beginning the transaction.
+func retryBusy(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > busyRetries || !isSQLiteBusy(err) || inTransaction(ctx) {
+			return err
+		}
+
+		// Back off before trying again, giving up if the caller stops waiting
+		timer := time.NewTimer(busyBackoff * time.Duration(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// isOutage reports whether err is the database being unreachable or failing rather than rejecting a query:
+// a transient connection error, exhausted server resources (class 53), or a system or internal error (58, XX)
+func isOutage(err error) bool {
+	if isTransientError(err) || stderrors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		for _, class := range []string{"53", "58", "XX"} {
+			if strings.HasPrefix(pgErr.Code, class) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inTransaction reports whether ctx carries a transaction
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// isTransientError reports whether err is a lost or refused connection, which a retry may succeed past.
+// Query errors such as constraint violations or missing rows are never transient.
+func isTransientError(err error) bool {
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if stderrors.Is(err, driver.ErrBadConn) ||
+		stderrors.Is(err, io.EOF) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) ||
+		stderrors.Is(err, syscall.ECONNRESET) ||
+		stderrors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	// Postgres connection exceptions (class 08) and server shutdowns (57P01-57P03)
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", "57P02", "57P03":
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, "08")
+	}
+	return pgconn.SafeToRetry(err)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+)
+
+// retryingUserRepository retries the reads of a UserRepository after transient connection errors
+type retryingUserRepository struct {
+	next   UserRepository
+	policy RetryPolicy
+}
+
+// NewRetryingUserRepository wraps next so that reads are retried according to policy, writes are passed through
+func NewRetryingUserRepository(next UserRepository, policy RetryPolicy) UserRepository {
+	return &retryingUserRepository{
+		next:   next,
+		policy: policy,
+	}
+}
+
+func (r *retryingUserRepository) FindAll(ctx context.Context, page model.Page) ([]model.User, error) {
+	return retryRead(ctx, r.policy, func() ([]model.User, error) { return r.next.FindAll(ctx, page) })
+}
+
+func (r *retryingUserRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
+	return retryRead(ctx, r.policy, func() (*model.User, error) { return r.next.FindByID(ctx, id) })
+}
+
+func (r *retryingUserRepository) FindByIDUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	return retryRead(ctx, r.policy, func() (*model.User, error) { return r.next.FindByIDUnscoped(ctx, id) })
+}
+
+func (r *retryingUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return retryRead(ctx, r.policy, func() (*model.User, error) { return r.next.FindByEmail(ctx, email) })
+}
+
+func (r *retryingUserRepository) FindByEmails(ctx context.Context, emails []string) ([]model.User, error) {
+	return retryRead(ctx, r.policy, func() ([]model.User, error) { return r.next.FindByEmails(ctx, emails) })
+}
+
+func (r *retryingUserRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	return retryRead(ctx, r.policy, func() (bool, error) { return r.next.Exists(ctx, id) })
+}
+
+func (r *retryingUserRepository) LockActiveIDsInRole(ctx context.Context, role string) ([]uint, error) {
+	return retryRead(ctx, r.policy, func() ([]uint, error) { return r.next.LockActiveIDsInRole(ctx, role) })
+}
+
+func (r *retryingUserRepository) CountByRoleAndStatus(ctx context.Context) ([]model.UserRoleStatusCount, error) {
+	return retryRead(ctx, r.policy, func() ([]model.UserRoleStatusCount, error) { return r.next.CountByRoleAndStatus(ctx) })
+}
+
+func (r *retryingUserRepository) Create(ctx context.Context, user *model.User) error {
+	return r.next.Create(ctx, user)
+}
+
+func (r *retryingUserRepository) Update(ctx context.Context, user *model.User) error {
+	return r.next.Update(ctx, user)
+}
+
+func (r *retryingUserRepository) Delete(ctx context.Context, id uint) error {
+	return r.next.Delete(ctx, id)
+}
+
+func (r *retryingUserRepository) UpdateActiveStatus(ctx context.Context, ids []uint, active bool) ([]uint, error) {
+	return r.next.UpdateActiveStatus(ctx, ids, active)
+}
+
+// retryingRoleRepository retries the reads of a RoleRepository after transient connection errors
+type retryingRoleRepository struct {
+	next   RoleRepository
+	policy RetryPolicy
+}
+
+// NewRetryingRoleRepository wraps next so that reads are retried according to policy
+func NewRetryingRoleRepository(next RoleRepository, policy RetryPolicy) RoleRepository {
+	return &retryingRoleRepository{
+		next:   next,
+		policy: policy,
+	}
+}
+
+func (r *retryingRoleRepository) Exists(ctx context.Context, name string) (bool, error) {
+	return retryRead(ctx, r.policy, func() (bool, error) { return r.next.Exists(ctx, name) })
+}
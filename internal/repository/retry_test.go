@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// failFirst registers a callback on processor that fails its first failures calls with err and counts every call
+func failFirst(t *testing.T, processor interface {
+	Register(name string, fn func(*gorm.DB)) error
+}, failures int, err error) *int {
+	calls := 0
+	assert.NoError(t, processor.Register("test:fail_first", func(tx *gorm.DB) {
+		calls++
+		if calls <= failures {
+			_ = tx.AddError(err)
+		}
+	}))
+	return &calls
+}
+
+func TestRetryingUserRepositoryRead(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		failures      int
+		expectedCalls int
+		expectErr     bool
+	}{
+		{name: "RecoversFromDroppedConnection", err: driver.ErrBadConn, failures: 1, expectedCalls: 2},
+		{name: "RecoversFromFailover", err: &pgconn.PgError{Code: "57P01"}, failures: 2, expectedCalls: 3},
+		{name: "GivesUpAfterRetries", err: driver.ErrBadConn, failures: 5, expectedCalls: 3, expectErr: true},
+		{name: "DoesNotRetryQueryErrors", err: stderrors.New("no such column"), failures: 1, expectedCalls: 1, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			users := seedUsers(t, db, model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+			repo := NewRetryingUserRepository(NewUserRepository(db), RetryPolicy{Retries: 2, Backoff: time.Millisecond})
+
+			// Fail the first reads
+			calls := failFirst(t, db.Callback().Query().Before("gorm:query"), tc.failures, tc.err)
+
+			user, err := repo.FindByID(context.Background(), users[0].ID)
+
+			assert.Equal(t, tc.expectedCalls, *calls)
+			if tc.expectErr {
+				var appErr *errors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user1@example.com", user.Email)
+		})
+	}
+}
+
+func TestRetryingUserRepositoryWritesNotRetried(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewRetryingUserRepository(NewUserRepository(db), RetryPolicy{Retries: 2, Backoff: time.Millisecond})
+
+	// Fail the first insert with a dropped connection
+	calls := failFirst(t, db.Callback().Create().Before("gorm:create"), 1, driver.ErrBadConn)
+
+	err := repo.Create(context.Background(), &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryReadInTransaction(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewRetryingUserRepository(NewUserRepository(db), RetryPolicy{Retries: 2, Backoff: time.Millisecond})
+	calls := failFirst(t, db.Callback().Query().Before("gorm:query"), 1, driver.ErrBadConn)
+
+	// A dropped connection can't be retried within the transaction
+	err := NewTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := repo.FindAll(ctx, model.Page{})
+		return err
+	})
+
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestWritesRetriedWhileSQLiteBusy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		failures      int
+		expectedCalls int
+		expectErr     bool
+	}{
+		{name: "RecoversFromLockedDatabase", err: stderrors.New("database is locked"), failures: 2, expectedCalls: 3},
+		{name: "GivesUpAfterRetries", err: stderrors.New("database is locked"), failures: 10, expectedCalls: busyRetries + 1, expectErr: true},
+		{name: "DoesNotRetryOtherErrors", err: driver.ErrBadConn, failures: 1, expectedCalls: 1, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDB(t)
+			repo := NewUserRepository(db)
+
+			// Fail the first inserts
+			calls := failFirst(t, db.Callback().Create().Before("gorm:create"), tc.failures, tc.err)
+
+			err := repo.Create(context.Background(), &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+
+			assert.Equal(t, tc.expectedCalls, *calls)
+			if tc.expectErr {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTransactionNotRerunWhileSQLiteBusy(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Fail every insert as locked
+	locked := stderrors.New("database is locked")
+	inserts := failFirst(t, db.Callback().Create().Before("gorm:create"), 10, locked)
+
+	// Run a transaction with caller logic before the write
+	runs := 0
+	err := NewTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+		runs++
+		return repo.Create(ctx, &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+	})
+
+	// Assert neither the caller logic nor the statement was repeated
+	assert.ErrorIs(t, err, locked)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 1, *inserts)
+}
+
+func TestTransactionRollsBackOnPanic(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Panic after writing within the transaction
+	assert.Panics(t, func() {
+		_ = NewTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+			assert.NoError(t, repo.Create(ctx, &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"}))
+			panic("service bug")
+		})
+	})
+
+	// Assert the write was rolled back
+	var count int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestConcurrentSQLiteWriters(t *testing.T) {
+	// Open a file database so every connection shares it, waiting on locks and taking the write lock when a transaction begins
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_busy_timeout=5000&_txlock=immediate"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.NoError(t, err)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	assert.NoError(t, migration.AutoMigrate(db))
+
+	repo := NewUserRepository(db)
+	transactor := NewTransactor(db)
+
+	// Create users from concurrent transactions
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+				return repo.Create(ctx, &model.User{Name: "User", Email: fmt.Sprintf("user%d@example.com", i), Password: "x"})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert every writer eventually succeeded
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	var count int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Equal(t, int64(writers), count)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository defines the interface for role repository
+type RoleRepository interface {
+	Exists(ctx context.Context, name string) (bool, error)
+}
+
+// roleRepositoryImpl implements the RoleRepository interface
+type roleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepositoryImpl{
+		db: db,
+	}
+}
+
+// Exists checks whether a role with the given name exists
+func (r *roleRepositoryImpl) Exists(ctx context.Context, name string) (bool, error) {
+	var count int64
+	result := conn(ctx, r.db).Model(&model.Role{}).Where("name = ?", name).Count(&count)
+	if result.Error != nil {
+		return false, errors.NewDatabaseError("Failed to check role", result.Error)
+	}
+	return count > 0, nil
+}
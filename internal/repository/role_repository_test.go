@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleExists(t *testing.T) {
+	// Seed roles table
+	db := newTestDB(t)
+	assert.NoError(t, db.Create(&[]model.Role{{Name: "admin"}, {Name: "user"}}).Error)
+
+	repo := NewRoleRepository(db)
+
+	testCases := []struct {
+		name     string
+		role     string
+		expected bool
+	}{
+		{name: "KnownRole", role: "admin", expected: true},
+		{name: "UnknownRole", role: "superuser", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exists, err := repo.Exists(context.Background(), tc.role)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, exists)
+		})
+	}
+}
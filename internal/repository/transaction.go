@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs functions within a database transaction
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction carried by the context passed to it.
+	// The transaction commits if fn returns nil and rolls back if it returns an error or panics.
+	// Calls nested in an existing transaction join it.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// gormTransactor implements the Transactor interface
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new transactor
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn within a transaction
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	// Retry only beginning the transaction while SQLite is locked, fn runs at most once.
+	// With immediate locking (_txlock=immediate in the DSN) the write lock is taken on BEGIN, so statements within fn don't wait on it.
+	var tx *gorm.DB
+	err := retryBusy(ctx, func() error {
+		tx = t.db.WithContext(ctx).Begin()
+		return tx.Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// Roll back unless committed, including when fn panics
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// conn returns the transaction carried by the context, or db if there is none
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
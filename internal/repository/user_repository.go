@@ -6,16 +6,23 @@ import (
 	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository defines the interface for user repository
 type UserRepository interface {
-	FindAll(ctx context.Context) ([]model.User, error)
+	FindAll(ctx context.Context, page model.Page) ([]model.User, error)
 	FindByID(ctx context.Context, id uint) (*model.User, error)
+	FindByIDUnscoped(ctx context.Context, id uint) (*model.User, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByEmails(ctx context.Context, emails []string) ([]model.User, error)
+	Exists(ctx context.Context, id uint) (bool, error)
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, user *model.User) error
 	Delete(ctx context.Context, id uint) error
+	UpdateActiveStatus(ctx context.Context, ids []uint, active bool) ([]uint, error)
+	CountByRoleAndStatus(ctx context.Context) ([]model.UserRoleStatusCount, error)
+	LockActiveIDsInRole(ctx context.Context, role string) ([]uint, error)
 }
 
 // userRepositoryImpl implements the UserRepository interface
@@ -30,10 +37,14 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
-// FindAll retrieves all users
-func (r *userRepositoryImpl) FindAll(ctx context.Context) ([]model.User, error) {
+// FindAll retrieves the users within page, ordered by ID so pages are stable
+func (r *userRepositoryImpl) FindAll(ctx context.Context, page model.Page) ([]model.User, error) {
 	var users []model.User
-	result := r.db.WithContext(ctx).Find(&users)
+	query := conn(ctx, r.db).Order("id")
+	if page.Limit > 0 {
+		query = query.Offset(page.Offset).Limit(page.Limit)
+	}
+	result := query.Find(&users)
 	if result.Error != nil {
 		return nil, errors.NewDatabaseError("Failed to retrieve users", result.Error)
 	}
@@ -43,7 +54,7 @@ func (r *userRepositoryImpl) FindAll(ctx context.Context) ([]model.User, error)
 // FindByID retrieves a user by ID
 func (r *userRepositoryImpl) FindByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	result := r.db.WithContext(ctx).First(&user, id)
+	result := conn(ctx, r.db).First(&user, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, errors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": id}, result.Error)
@@ -53,10 +64,23 @@ func (r *userRepositoryImpl) FindByID(ctx context.Context, id uint) (*model.User
 	return &user, nil
 }
 
-// FindByEmail retrieves a user by email
+// FindByIDUnscoped retrieves a user by ID, including soft-deleted users
+func (r *userRepositoryImpl) FindByIDUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	var user model.User
+	result := conn(ctx, r.db).Unscoped().First(&user, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, errors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": id}, result.Error)
+		}
+		return nil, errors.NewDatabaseError("Failed to retrieve user", result.Error)
+	}
+	return &user, nil
+}
+
+// FindByEmail retrieves a user by email, ignoring case like the unique index on LOWER(email)
 func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := conn(ctx, r.db).Where("LOWER(email) = LOWER(?)", email).First(&user)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, errors.NewResourceNotFoundError("User not found", map[string]interface{}{"email": email}, result.Error)
@@ -66,27 +90,53 @@ func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*mo
 	return &user, nil
 }
 
+// FindByEmails retrieves the users whose lowercased email is in emails, which must already be lowercased
+func (r *userRepositoryImpl) FindByEmails(ctx context.Context, emails []string) ([]model.User, error) {
+	var users []model.User
+	result := conn(ctx, r.db).Where("LOWER(email) IN ?", emails).Order("id").Find(&users)
+	if result.Error != nil {
+		return nil, errors.NewDatabaseError("Failed to retrieve users by email", result.Error)
+	}
+	return users, nil
+}
+
+// Exists checks whether a user with the given ID exists
+func (r *userRepositoryImpl) Exists(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	result := conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).Count(&count)
+	if result.Error != nil {
+		return false, errors.NewDatabaseError("Failed to check user existence", result.Error)
+	}
+	return count > 0, nil
+}
+
 // Create creates a new user
 func (r *userRepositoryImpl) Create(ctx context.Context, user *model.User) error {
-	// Check if user with the same email already exists
+	// Check if user with the same email already exists, only a not found result lets the insert proceed
 	existingUser, err := r.FindByEmail(ctx, user.Email)
 	if err == nil && existingUser != nil {
 		return errors.NewDuplicateResourceError("User with this email already exists", map[string]interface{}{"email": user.Email}, nil)
 	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 
 	// Create user
-	result := r.db.WithContext(ctx).Create(&user)
-	if result.Error != nil {
-		return errors.NewDatabaseError("Failed to create user", result.Error)
+	if err := retryBusy(ctx, func() error { return conn(ctx, r.db).Create(&user).Error }); err != nil {
+		return writeError("Failed to create user", err)
 	}
 	return nil
 }
 
 // Update updates a user
 func (r *userRepositoryImpl) Update(ctx context.Context, user *model.User) error {
-	result := r.db.WithContext(ctx).Save(&user)
-	if result.Error != nil {
-		return errors.NewDatabaseError("Failed to update user", result.Error)
+	var result *gorm.DB
+	err := retryBusy(ctx, func() error {
+		result = conn(ctx, r.db).Save(&user)
+		return result.Error
+	})
+	if err != nil {
+		return writeError("Failed to update user", err)
 	}
 	if result.RowsAffected == 0 {
 		return errors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": user.ID}, nil)
@@ -96,12 +146,71 @@ func (r *userRepositoryImpl) Update(ctx context.Context, user *model.User) error
 
 // Delete deletes a user
 func (r *userRepositoryImpl) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&model.User{}, id)
-	if result.Error != nil {
-		return errors.NewDatabaseError("Failed to delete user", result.Error)
+	var result *gorm.DB
+	err := retryBusy(ctx, func() error {
+		result = conn(ctx, r.db).Delete(&model.User{}, id)
+		return result.Error
+	})
+	if err != nil {
+		return errors.NewDatabaseError("Failed to delete user", err)
 	}
 	if result.RowsAffected == 0 {
 		return errors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": id}, nil)
 	}
 	return nil
 }
+
+// UpdateActiveStatus sets the active flag for all existing users in ids within a transaction
+// and returns the IDs that were updated
+func (r *userRepositoryImpl) UpdateActiveStatus(ctx context.Context, ids []uint, active bool) ([]uint, error) {
+	var updatedIDs []uint
+	err := retryBusy(ctx, func() error {
+		updatedIDs = nil
+		return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+			// Find the users that exist
+			if err := tx.Model(&model.User{}).Where("id IN ?", ids).Order("id").Pluck("id", &updatedIDs).Error; err != nil {
+				return err
+			}
+			if len(updatedIDs) == 0 {
+				return nil
+			}
+
+			// Update them together
+			return tx.Model(&model.User{}).Where("id IN ?", updatedIDs).Update("active", active).Error
+		})
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("Failed to update user status", err)
+	}
+	return updatedIDs, nil
+}
+
+// LockActiveIDsInRole returns the IDs of the active users holding role and locks their rows with
+// SELECT ... FOR UPDATE until the surrounding transaction ends, so concurrent changes to them are
+// checked one after another. SQLite ignores the lock, its writing transactions are serialized instead.
+func (r *userRepositoryImpl) LockActiveIDsInRole(ctx context.Context, role string) ([]uint, error) {
+	var ids []uint
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND active = ?", role, true).
+		Order("id").
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, errors.NewDatabaseError("Failed to lock users", result.Error)
+	}
+	return ids, nil
+}
+
+// CountByRoleAndStatus counts users grouped by role and active status in a single query
+func (r *userRepositoryImpl) CountByRoleAndStatus(ctx context.Context) ([]model.UserRoleStatusCount, error) {
+	var counts []model.UserRoleStatusCount
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Select("role, active, COUNT(*) AS count").
+		Group("role, active").
+		Order("role, active").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, errors.NewDatabaseError("Failed to count users", result.Error)
+	}
+	return counts, nil
+}
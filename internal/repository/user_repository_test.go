@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// seedUsers inserts users and returns them with their assigned IDs
+func seedUsers(t *testing.T, db *gorm.DB, users ...model.User) []model.User {
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	return users
+}
+
+func TestUpdateActiveStatus(t *testing.T) {
+	db := newTestDB(t)
+	users := seedUsers(t, db,
+		model.User{Name: "User 1", Email: "user1@example.com", Password: "x", Active: true},
+		model.User{Name: "User 2", Email: "user2@example.com", Password: "x", Active: true},
+		model.User{Name: "User 3", Email: "user3@example.com", Password: "x", Active: true},
+	)
+	repo := NewUserRepository(db)
+
+	// Deactivate two existing users and one missing ID
+	updatedIDs, err := repo.UpdateActiveStatus(context.Background(), []uint{users[0].ID, users[2].ID, 99}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{users[0].ID, users[2].ID}, updatedIDs)
+
+	// Assert only the requested users changed
+	var inactive []model.User
+	assert.NoError(t, db.Where("active = ?", false).Order("id").Find(&inactive).Error)
+	assert.Len(t, inactive, 2)
+	assert.Equal(t, users[0].ID, inactive[0].ID)
+	assert.Equal(t, users[2].ID, inactive[1].ID)
+}
+
+func TestEmailUniqueIgnoresCase(t *testing.T) {
+	db := newTestDB(t)
+	seedUsers(t, db, model.User{Name: "User 1", Email: "A@x.com", Password: "x"})
+
+	// Insert a case variant directly, bypassing the repository's duplicate check
+	err := db.Create(&model.User{Name: "User 2", Email: "a@x.com", Password: "x"}).Error
+
+	// Assert the index rejects it
+	assert.Error(t, err)
+	var count int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestEmailCaseVariantIsDuplicate(t *testing.T) {
+	db := newTestDB(t)
+	users := seedUsers(t, db,
+		model.User{Name: "User 1", Email: "a@x.com", Password: "x"},
+		model.User{Name: "User 2", Email: "b@x.com", Password: "x"},
+	)
+	repo := NewUserRepository(db)
+
+	// Lookups ignore case
+	found, err := repo.FindByEmail(context.Background(), "A@X.com")
+	assert.NoError(t, err)
+	assert.Equal(t, users[0].ID, found.ID)
+
+	// Creating a case variant is rejected by the pre-check
+	err = repo.Create(context.Background(), &model.User{Name: "User 3", Email: "A@x.com", Password: "x"})
+	var appErr *errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrCodeDuplicateResource, appErr.Code)
+	assert.False(t, appErr.Retryable)
+
+	// Updating to a case variant is rejected by the index
+	user := users[1]
+	user.Email = "A@X.COM"
+	err = repo.Update(context.Background(), &user)
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrCodeDuplicateResource, appErr.Code)
+	assert.NotContains(t, appErr.Message, "UNIQUE")
+}
+
+func TestExists(t *testing.T) {
+	db := newTestDB(t)
+	users := seedUsers(t, db, model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+	repo := NewUserRepository(db)
+
+	testCases := []struct {
+		name     string
+		id       uint
+		expected bool
+	}{
+		{name: "Existing", id: users[0].ID, expected: true},
+		{name: "Missing", id: users[0].ID + 1, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exists, err := repo.Exists(context.Background(), tc.id)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, exists)
+		})
+	}
+}
+
+func TestFindByIDSoftDeleted(t *testing.T) {
+	db := newTestDB(t)
+	users := seedUsers(t, db, model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+	repo := NewUserRepository(db)
+
+	// Soft delete the user
+	assert.NoError(t, repo.Delete(context.Background(), users[0].ID))
+
+	// Assert the default lookup hides the user
+	user, err := repo.FindByID(context.Background(), users[0].ID)
+	assert.Nil(t, user)
+	assert.True(t, errors.IsNotFound(err))
+
+	// Assert the unscoped lookup returns it with its deletion time
+	user, err = repo.FindByIDUnscoped(context.Background(), users[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1@example.com", user.Email)
+	assert.True(t, user.DeletedAt.Valid)
+
+	// Assert missing users are still not found
+	user, err = repo.FindByIDUnscoped(context.Background(), users[0].ID+1)
+	assert.Nil(t, user)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// userWithNickname is the users schema plus a required column without a default
+type userWithNickname struct {
+	model.User
+	Nickname string `gorm:"size:50;not null"`
+}
+
+func (*userWithNickname) TableName() string {
+	return "users"
+}
+
+func TestCreateMissingNotNullColumn(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Recreate the table with a NOT NULL column the model doesn't know about, as a later migration might
+	assert.NoError(t, db.Migrator().DropTable(&model.User{}))
+	assert.NoError(t, db.AutoMigrate(&userWithNickname{}))
+
+	// Insert a user without the new column
+	err := repo.Create(context.Background(), &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+
+	// Assert the error names the missing column
+	var appErr *errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrCodeInternal, appErr.Code)
+	assert.Equal(t, "Failed to create user: column nickname requires a value", appErr.Message)
+	assert.Equal(t, map[string]interface{}{"column": "nickname"}, appErr.Details)
+}
+
+func TestFindByEmails(t *testing.T) {
+	db := newTestDB(t)
+	seedUsers(t, db,
+		model.User{Name: "User 1", Email: "User1@Example.com", Password: "x"},
+		model.User{Name: "User 2", Email: "user2@example.com", Password: "x"},
+		model.User{Name: "User 3", Email: "user3@example.com", Password: "x"},
+	)
+	repo := NewUserRepository(db)
+
+	// Look up lowercased emails, one of them unknown
+	users, err := repo.FindByEmails(context.Background(), []string{"user1@example.com", "user3@example.com", "missing@example.com"})
+
+	// Assert matches are case-insensitive
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "User1@Example.com", users[0].Email)
+	assert.Equal(t, "user3@example.com", users[1].Email)
+}
+
+func TestCreateAbortsOnLookupError(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Fail every query, so the email pre-check errors while inserts would still succeed
+	lookupErr := stderrors.New("connection reset")
+	assert.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:fail_query", func(tx *gorm.DB) {
+		_ = tx.AddError(lookupErr)
+	}))
+
+	// Create a user
+	err := repo.Create(context.Background(), &model.User{Name: "User 1", Email: "user1@example.com", Password: "x"})
+
+	// Assert the create surfaced the lookup error instead of inserting
+	var appErr *errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrCodeDatabase, appErr.Code)
+	assert.ErrorIs(t, err, lookupErr)
+
+	assert.NoError(t, db.Callback().Query().Remove("test:fail_query"))
+	var count int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCountByRoleAndStatus(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// No users yields no groups
+	counts, err := repo.CountByRoleAndStatus(context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	// Seed users of mixed roles and status, including a soft-deleted one
+	users := seedUsers(t, db,
+		model.User{Name: "Admin 1", Email: "admin1@example.com", Password: "x", Role: model.RoleAdmin, Active: true},
+		model.User{Name: "User 1", Email: "user1@example.com", Password: "x", Role: model.RoleUser, Active: true},
+		model.User{Name: "User 2", Email: "user2@example.com", Password: "x", Role: model.RoleUser, Active: true},
+		model.User{Name: "User 3", Email: "user3@example.com", Password: "x", Role: model.RoleUser, Active: true},
+		model.User{Name: "User 4", Email: "user4@example.com", Password: "x", Role: model.RoleUser, Active: true},
+	)
+	assert.NoError(t, db.Model(&model.User{}).Where("id = ?", users[2].ID).Update("active", false).Error)
+	assert.NoError(t, db.Delete(&model.User{}, users[3].ID).Error)
+
+	counts, err = repo.CountByRoleAndStatus(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserRoleStatusCount{
+		{Role: model.RoleAdmin, Active: true, Count: 1},
+		{Role: model.RoleUser, Active: false, Count: 1},
+		{Role: model.RoleUser, Active: true, Count: 2},
+	}, counts)
+}
+
+func TestLockActiveIDsInRole(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	// Seed two active admins, an inactive admin and a user
+	users := seedUsers(t, db,
+		model.User{Name: "Admin 1", Email: "admin1@example.com", Password: "x", Role: model.RoleAdmin, Active: true},
+		model.User{Name: "Admin 2", Email: "admin2@example.com", Password: "x", Role: model.RoleAdmin, Active: true},
+		model.User{Name: "Admin 3", Email: "admin3@example.com", Password: "x", Role: model.RoleAdmin, Active: true},
+		model.User{Name: "User 1", Email: "user1@example.com", Password: "x", Role: model.RoleUser, Active: true},
+	)
+	assert.NoError(t, db.Model(&model.User{}).Where("id = ?", users[2].ID).Update("active", false).Error)
+
+	// Return only the active admins, within a transaction as the service calls it
+	var ids []uint
+	err := NewTransactor(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+		var err error
+		ids, err = repo.LockActiveIDsInRole(ctx, model.RoleAdmin)
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{users[0].ID, users[1].ID}, ids)
+}
+
+func TestLockActiveIDsInRoleLocksRowsOnPostgres(t *testing.T) {
+	// Build the statement for Postgres without connecting
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	var statement string
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Statement.SQL.String()
+	}))
+
+	_, err = NewUserRepository(db).LockActiveIDsInRole(context.Background(), model.RoleAdmin)
+
+	// Assert the active admin rows are locked until the transaction ends
+	assert.NoError(t, err)
+	assert.Contains(t, statement, "WHERE (role = $1 AND active = $2)")
+	assert.True(t, strings.HasSuffix(statement, "FOR UPDATE"), statement)
+}
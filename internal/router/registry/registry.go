@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Registry records route registrations and reports conflicts as errors instead of panicking
+type Registry struct {
+	routes map[string]string
+	errs   []error
+}
+
+// Group is a route group whose registrations are checked by a Registry
+type Group struct {
+	registry *Registry
+	group    *gin.RouterGroup
+}
+
+// New creates a new route registry
+func New() *Registry {
+	return &Registry{
+		routes: make(map[string]string),
+	}
+}
+
+// Wrap returns a checked group registering routes on the given gin group
+func (r *Registry) Wrap(group *gin.RouterGroup) *Group {
+	return &Group{
+		registry: r,
+		group:    group,
+	}
+}
+
+// Err returns the registration errors, or nil if every route was registered cleanly
+func (r *Registry) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// Group creates a checked sub-group
+func (g *Group) Group(relativePath string, handlers ...gin.HandlerFunc) *Group {
+	return g.registry.Wrap(g.group.Group(relativePath, handlers...))
+}
+
+// Handle registers a route unless the same method and path are already registered
+func (g *Group) Handle(method, relativePath string, handlers ...gin.HandlerFunc) {
+	fullPath := joinPaths(g.group.BasePath(), relativePath)
+	key := method + " " + routeShape(fullPath)
+
+	if existing, ok := g.registry.routes[key]; ok {
+		g.registry.errs = append(g.registry.errs,
+			fmt.Errorf("duplicate route %s %s: already registered as %s %s", method, fullPath, method, existing))
+		return
+	}
+
+	// Report any other conflict gin detects as an error
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			g.registry.errs = append(g.registry.errs, fmt.Errorf("invalid route %s %s: %v", method, fullPath, recovered))
+		}
+	}()
+
+	g.group.Handle(method, relativePath, handlers...)
+	g.registry.routes[key] = fullPath
+}
+
+// GET registers a GET route
+func (g *Group) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+// POST registers a POST route
+func (g *Group) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+// PUT registers a PUT route
+func (g *Group) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+// PATCH registers a PATCH route
+func (g *Group) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+// DELETE registers a DELETE route
+func (g *Group) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+// joinPaths joins a base path and a relative path the way gin does
+func joinPaths(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	joined := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
+
+// routeShape replaces parameter names so that /users/:id and /users/:uid compare equal
+func routeShape(fullPath string) string {
+	segments := strings.Split(fullPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = ":"
+		} else if strings.HasPrefix(segment, "*") {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,55 @@
+package registry
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func noop(*gin.Context) {}
+
+func TestDuplicateRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routes := New()
+	api := routes.Wrap(&engine.RouterGroup).Group("/api/v1")
+
+	// Register the same route twice, once through a different group
+	api.GET("/users/:id", noop)
+	routes.Wrap(&engine.RouterGroup).Group("/api").Group("/v1").GET("/users/:uid", noop)
+
+	// Assert a descriptive error instead of a panic
+	err := routes.Err()
+	assert.EqualError(t, err, "duplicate route GET /api/v1/users/:uid: already registered as GET /api/v1/users/:id")
+}
+
+func TestDistinctRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routes := New()
+	users := routes.Wrap(&engine.RouterGroup).Group("/users")
+
+	// Same path with different methods is fine
+	users.GET("", noop)
+	users.POST("", noop)
+	users.GET("/:id", noop)
+	users.DELETE("/:id", noop)
+
+	assert.NoError(t, routes.Err())
+	assert.Len(t, engine.Routes(), 4)
+}
+
+func TestGinConflictReportedAsError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routes := New()
+	root := routes.Wrap(&engine.RouterGroup)
+
+	// Conflicting wildcards make gin panic
+	root.Handle(http.MethodGet, "/files/*path", noop)
+	assert.NotPanics(t, func() { root.Handle(http.MethodGet, "/files/:name", noop) })
+
+	assert.ErrorContains(t, routes.Err(), "invalid route GET /files/:name")
+}
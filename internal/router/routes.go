@@ -1,38 +1,114 @@
 package router
 
 import (
+	"net"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ladderseeker/gin-crud-starter/config"
 	"github.com/ladderseeker/gin-crud-starter/internal/controller/v1"
+	"github.com/ladderseeker/gin-crud-starter/internal/health"
 	"github.com/ladderseeker/gin-crud-starter/internal/middleware"
 	"github.com/ladderseeker/gin-crud-starter/internal/repository"
+	"github.com/ladderseeker/gin-crud-starter/internal/router/registry"
 	"github.com/ladderseeker/gin-crud-starter/internal/service"
+	"github.com/ladderseeker/gin-crud-starter/internal/validation"
+	"github.com/ladderseeker/gin-crud-starter/pkg/version"
 	"gorm.io/gorm"
 )
 
-// SetupRoutes configures all the router for the application
-func SetupRoutes(router *gin.Engine, db *gorm.DB) {
+// SetupRoutes configures all the router for the application.
+// It returns an error if the custom validators fail to register or any route is registered twice.
+func SetupRoutes(router *gin.Engine, db *gorm.DB, conf *config.Config) error {
+
+	// Register custom binding validators
+	if err := validation.Register(binding.Validator.Engine()); err != nil {
+		return err
+	}
 
 	// Initialize user related instance
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
+	roleRepo := repository.NewRoleRepository(db)
+
+	// Retry reads through transient connection errors, such as a failover
+	if conf.Database.ReadRetries > 0 {
+		policy := repository.RetryPolicy{Retries: conf.Database.ReadRetries, Backoff: conf.Database.ReadRetryBackoff}
+		userRepo = repository.NewRetryingUserRepository(userRepo, policy)
+		roleRepo = repository.NewRetryingRoleRepository(roleRepo, policy)
+	}
+
+	// Fail fast while the database is failing
+	if conf.Database.BreakerThreshold > 0 {
+		breaker := repository.NewCircuitBreaker(conf.Database.BreakerThreshold, conf.Database.BreakerCooldown)
+		userRepo = repository.NewCircuitBreakerUserRepository(userRepo, breaker)
+		roleRepo = repository.NewCircuitBreakerRoleRepository(roleRepo, breaker)
+	}
+	userOptions := []service.UserServiceOption{service.WithReservedNames(service.NewReservedNames(conf.Users.ReservedNames))}
+	if conf.Users.NormalizeNames {
+		userOptions = append(userOptions, service.WithNameNormalization())
+	}
+	if conf.Email.ValidateMX {
+		userOptions = append(userOptions, service.WithEmailMXValidation(net.DefaultResolver, conf.Email.MXTimeout))
+	}
+	userService := service.NewTransactionalUserService(
+		service.NewUserService(userRepo, roleRepo, userOptions...),
+		repository.NewTransactor(db),
+	)
 	userController := v1.NewUserController(userService)
 
+	// Bound the structure of inbound JSON bodies
+	v1.SetJSONLimits(conf.Server.JSONMaxDepth, conf.Server.JSONMaxKeys)
+	v1.SetStrictJSON(conf.Server.StrictJSON)
+
+	// Cap list responses regardless of the requested page size
+	v1.SetMaxResponseItems(conf.Server.MaxResponseItems)
+
 	// Setup middleware
-	middleware.SetupMiddleware(router)
+	middleware.SetupMiddleware(router, conf)
+
+	// Track registrations to report duplicates
+	routes := registry.New()
+	root := routes.Wrap(&router.RouterGroup)
 
 	// Health check route
-	router.GET("/health", func(c *gin.Context) {
+	root.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status": "ok",
 		})
 	})
 
+	// Build version
+	root.GET("/version", func(c *gin.Context) {
+		c.JSON(200, version.Get())
+	})
+
+	// Readiness probe, dependency results are cached briefly so frequent probes don't add load
+	readiness := health.NewChecker(conf.Server.ReadinessTTL, 2*time.Second)
+	readiness.Add("database", health.DatabaseCheck(db))
+	if conf.Server.ReadinessWrite {
+		readiness.Add("database_write", health.DatabaseWriteCheck(db))
+	}
+	root.GET("/readyz", readiness.Handler)
+
+	// Prometheus metrics
+	root.GET("/metrics", middleware.Metrics())
+
 	// API router
-	api := router.Group("/api/v1")
+	api := root.Group("/api/v1")
 	{
 		userController.Register(api)
 	}
 
+	// Handle 405 Method Not Allowed, gin sets the Allow header from the registered routes
+	router.HandleMethodNotAllowed = true
+	router.NoMethod(func(c *gin.Context) {
+		c.JSON(405, gin.H{
+			"code":    "METHOD_NOT_ALLOWED",
+			"message": "The requested method is not allowed for this resource",
+		})
+	})
+
 	// Handle 404 Not Found
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(404, gin.H{
@@ -40,4 +116,21 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 			"message": "The requested resource was not found",
 		})
 	})
+
+	return routes.Err()
+}
+
+// SetupDebugRoutes configures the runtime diagnostics router.
+// It is served on its own listener so the diagnostics never share the public API's address.
+func SetupDebugRoutes(router *gin.Engine, db *gorm.DB, conf *config.Config) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	router.Use(gin.Recovery())
+
+	routes := registry.New()
+	v1.NewAdminController(sqlDB, conf.Server.DebugPprof).Register(routes.Wrap(&router.RouterGroup))
+	return routes.Err()
 }
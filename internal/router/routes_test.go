@@ -0,0 +1,189 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/config"
+	"github.com/ladderseeker/gin-crud-starter/internal/migration"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRouter creates a fully configured router backed by an in-memory database
+func newTestRouter(t *testing.T) *gin.Engine {
+	router, _ := newTestRouterWithDB(t)
+	return router
+}
+
+// newTestRouterWithDB creates a fully configured router and returns its migrated in-memory database
+func newTestRouterWithDB(t *testing.T) (*gin.Engine, *gorm.DB) {
+	db := newTestDB(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := SetupRoutes(router, db, &config.Config{}); err != nil {
+		t.Fatalf("failed to setup routes: %v", err)
+	}
+	return router, db
+}
+
+// newTestDB opens a migrated in-memory database
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	// Keep a single connection so every query sees the same in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get test database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := migration.AutoMigrate(db); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return db
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	router := newTestRouter(t)
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{name: "DeleteOnGetOnlyRoute", method: http.MethodDelete, path: "/health", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET"},
+		{name: "PatchOnCollection", method: http.MethodPatch, path: "/api/v1/users", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, POST"},
+		{name: "UnknownPath", method: http.MethodGet, path: "/unknown", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedAllow, w.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestReadinessProbe(t *testing.T) {
+	router := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"ok","checks":{"database":"ok"}}`, w.Body.String())
+}
+
+func TestListUsersPaginationStyles(t *testing.T) {
+	router, db := newTestRouterWithDB(t)
+
+	// Seed users
+	users := make([]model.User, 0, 35)
+	for i := 1; i <= 35; i++ {
+		users = append(users, model.User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Password: "x"})
+	}
+	assert.NoError(t, db.Create(&users).Error)
+
+	list := func(query string) (int, []model.UserResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users?"+query, nil))
+		var page []model.UserResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &page)
+		return w.Code, page
+	}
+
+	// Both styles address the same rows
+	code, byOffset := list("offset=20&limit=10")
+	assert.Equal(t, http.StatusOK, code)
+	code, byPage := list("page=3&page_size=10")
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.Len(t, byOffset, 10)
+	assert.Equal(t, byOffset, byPage)
+	assert.Equal(t, "user21@example.com", byOffset[0].Email)
+
+	// offset/limit take precedence when both are given
+	_, mixed := list("offset=20&limit=10&page=1&page_size=5")
+	assert.Equal(t, byOffset, mixed)
+
+	// No parameters lists every user
+	_, all := list("")
+	assert.Len(t, all, 35)
+
+	// Invalid values are rejected
+	code, _ = list("page=0")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = list("limit=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = list(fmt.Sprintf("page=%d&page_size=10", math.MaxInt))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestVersionRoute(t *testing.T) {
+	// Inject build metadata as ldflags would
+	originalVersion, originalCommit, originalBuildDate := version.Version, version.Commit, version.BuildDate
+	version.Version, version.Commit, version.BuildDate = "1.4.0", "abc1234", "2024-05-01T00:00:00Z"
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.BuildDate = originalVersion, originalCommit, originalBuildDate
+	})
+	router := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"version":"1.4.0","commit":"abc1234","build_date":"2024-05-01T00:00:00Z"}`, w.Body.String())
+}
+
+func TestDebugRoutesStayOffPublicAPI(t *testing.T) {
+	db := newTestDB(t)
+	conf := &config.Config{Server: config.ServerConfig{DebugEndpoints: true, DebugPprof: true}}
+
+	// Build the public and debug routers as the server does
+	gin.SetMode(gin.TestMode)
+	public := gin.New()
+	assert.NoError(t, SetupRoutes(public, db, conf))
+	debug := gin.New()
+	assert.NoError(t, SetupDebugRoutes(debug, db, conf))
+
+	testCases := []struct {
+		name           string
+		router         *gin.Engine
+		path           string
+		expectedStatus int
+	}{
+		{name: "PublicDiagnostics", router: public, path: "/api/v1/admin/debug", expectedStatus: http.StatusNotFound},
+		{name: "PublicPprof", router: public, path: "/api/v1/admin/debug/pprof/", expectedStatus: http.StatusNotFound},
+		{name: "DebugDiagnostics", router: debug, path: "/admin/debug", expectedStatus: http.StatusOK},
+		{name: "DebugPprof", router: debug, path: "/admin/debug/pprof/", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tc.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MXResolver looks up mail exchangers, *net.Resolver satisfies it
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// UserServiceOption configures optional user service behavior
+type UserServiceOption func(*userServiceImpl)
+
+// WithEmailMXValidation rejects emails whose domain can't receive mail, giving each lookup at most timeout
+func WithEmailMXValidation(resolver MXResolver, timeout time.Duration) UserServiceOption {
+	return func(s *userServiceImpl) {
+		s.mxResolver = resolver
+		s.mxTimeout = timeout
+	}
+}
+
+// validateEmailDomain checks that the email's domain has mail exchangers.
+// Only a definitive answer rejects the email, timeouts and other lookup failures let it through.
+func (s *userServiceImpl) validateEmailDomain(ctx context.Context, email string) error {
+	if s.mxResolver == nil {
+		return nil
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	lookupCtx, cancel := context.WithTimeout(ctx, s.mxTimeout)
+	defer cancel()
+
+	records, err := s.mxResolver.LookupMX(lookupCtx, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if stderrors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return errors.NewInvalidInputError("Email domain cannot receive mail", map[string]interface{}{"domain": domain}, err)
+		}
+		logger.FromContext(ctx).Warn("Skipping email domain check", zap.String("domain", domain), zap.Error(err))
+		return nil
+	}
+
+	// A single "." exchanger is a null MX, explicitly declaring the domain accepts no mail
+	if len(records) == 0 || (len(records) == 1 && records[0].Host == ".") {
+		return errors.NewInvalidInputError("Email domain cannot receive mail", map[string]interface{}{"domain": domain}, nil)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubMXResolver answers MX lookups from a fixed table, blocking until the context ends for unknown domains
+type stubMXResolver map[string][]*net.MX
+
+func (r stubMXResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if records, ok := r[name]; ok {
+		if records == nil {
+			return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		}
+		return records, nil
+	}
+	<-ctx.Done()
+	return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+}
+
+func TestCreateUserEmailMXValidation(t *testing.T) {
+	resolver := stubMXResolver{
+		"example.com": {{Host: "mail.example.com.", Pref: 10}},
+		"gmial.com":   nil,
+		"nomail.com":  {{Host: ".", Pref: 0}},
+	}
+
+	testCases := []struct {
+		name        string
+		email       string
+		expectError bool
+	}{
+		{name: "DomainWithMX", email: "user@example.com"},
+		{name: "UnknownDomain", email: "user@gmial.com", expectError: true},
+		{name: "NullMX", email: "user@nomail.com", expectError: true},
+		{name: "LookupTimeout", email: "user@slow.example"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockRoleRepo := new(MockRoleRepository)
+			mockRoleRepo.On("Exists", mock.Anything, "user").Return(true, nil)
+			service := NewUserService(mockRepo, mockRoleRepo, WithEmailMXValidation(resolver, 20*time.Millisecond))
+
+			// Execute
+			result, err := service.CreateUser(context.Background(), model.UserCreate{
+				Name:     "New User",
+				Email:    tc.email,
+				Password: "password123",
+			})
+
+			// Assert
+			if tc.expectError {
+				var appErr *apperrors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, apperrors.ErrCodeInvalidInput, appErr.Code)
+				assert.Nil(t, result)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.email, result.Email)
+			mockRepo.AssertCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
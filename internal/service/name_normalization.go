@@ -0,0 +1,40 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithNameNormalization stores user names in a canonical form, so names that render alike compare equal
+func WithNameNormalization() UserServiceOption {
+	return func(s *userServiceImpl) {
+		s.normalizeNames = true
+	}
+}
+
+// NormalizeName composes name to Unicode NFC, drops invisible format characters such as zero-width spaces,
+// and collapses runs of whitespace into single spaces, trimming the ends
+func NormalizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, norm.NFC.String(name))
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// normalizeName returns the stored form of name, rejecting names left blank by normalization
+func (s *userServiceImpl) normalizeName(name string) (string, error) {
+	if !s.normalizeNames {
+		return name, nil
+	}
+	normalized := NormalizeName(name)
+	if normalized == "" {
+		return "", errors.NewInvalidInputError("Name must not be blank", map[string]interface{}{"field": "name"}, nil)
+	}
+	return normalized, nil
+}
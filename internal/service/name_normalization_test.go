@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "TrailingZeroWidthSpace", input: "  Foo\u200b", expected: "Foo"},
+		{name: "CombiningAccent", input: "Jose\u0301 Garci\u0301a", expected: "Jos\u00e9 Garc\u00eda"},
+		{name: "CollapsedWhitespace", input: "Jane \t\n  Doe ", expected: "Jane Doe"},
+		{name: "ZeroWidthInsideWord", input: "Ja\u200dne\ufeff", expected: "Jane"},
+		{name: "Unchanged", input: "Jane Doe", expected: "Jane Doe"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeName(tc.input))
+		})
+	}
+}
+
+func TestCreateUserNormalizesName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []UserServiceOption
+		input    string
+		expected string
+	}{
+		{name: "Enabled", options: []UserServiceOption{WithNameNormalization()}, input: "  Jose\u0301\u200b  Doe ", expected: "Jos\u00e9 Doe"},
+		{name: "Disabled", input: "  Jose\u0301\u200b  Doe ", expected: "  Jose\u0301\u200b  Doe "},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			mockRoleRepo := new(MockRoleRepository)
+			mockRoleRepo.On("Exists", mock.Anything, "user").Return(true, nil)
+			service := NewUserService(mockRepo, mockRoleRepo, tc.options...)
+
+			// Execute
+			_, err := service.CreateUser(context.Background(), model.UserCreate{Name: tc.input, Email: "jose@example.com", Password: "password123"})
+
+			// Assert the stored form
+			assert.NoError(t, err)
+			stored := mockRepo.Calls[0].Arguments.Get(1).(*model.User)
+			assert.Equal(t, tc.expected, stored.Name)
+		})
+	}
+}
+
+func TestUpdateUserRejectsBlankNormalizedName(t *testing.T) {
+	// Setup
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.User{ID: 1, Name: "Jane", Email: "jane@example.com"}, nil)
+	service := NewUserService(mockRepo, new(MockRoleRepository), WithNameNormalization())
+
+	// Execute with a name made only of invisible characters
+	name := " \u200b\u200b "
+	result, err := service.UpdateUser(context.Background(), 1, model.UserUpdate{Name: &name})
+
+	// Assert
+	var appErr *apperrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.ErrCodeInvalidInput, appErr.Code)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
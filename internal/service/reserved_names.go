@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
+)
+
+// ReservedNames matches user names and emails kept back to avoid impersonation.
+// Entries match case-insensitively, exactly, or as a prefix when they end in "*".
+type ReservedNames struct {
+	exact    map[string]bool
+	prefixes []string
+}
+
+// NewReservedNames builds the matcher from entries such as "root" or "admin@*", ignoring blank entries
+func NewReservedNames(entries []string) *ReservedNames {
+	r := &ReservedNames{exact: make(map[string]bool)}
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "" || entry == "*":
+			continue
+		case strings.HasSuffix(entry, "*"):
+			r.prefixes = append(r.prefixes, strings.TrimSuffix(entry, "*"))
+		default:
+			r.exact[entry] = true
+		}
+	}
+	return r
+}
+
+// Match reports whether value is reserved
+func (r *ReservedNames) Match(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if r.exact[value] {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithReservedNames rejects creating or renaming users to a reserved name or email
+func WithReservedNames(reserved *ReservedNames) UserServiceOption {
+	return func(s *userServiceImpl) {
+		s.reserved = reserved
+	}
+}
+
+// checkReserved returns an invalid input error naming the field holding a reserved value
+func (s *userServiceImpl) checkReserved(field, value string) error {
+	if s.reserved == nil || !s.reserved.Match(value) {
+		return nil
+	}
+	return errors.NewInvalidInputError("This "+field+" is reserved", map[string]interface{}{"field": field}, nil)
+}
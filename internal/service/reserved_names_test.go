@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReservedNames(t *testing.T) {
+	reserved := NewReservedNames([]string{"root", " System ", "admin@*", ""})
+
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "root", expected: true},
+		{value: "ROOT", expected: true},
+		{value: "system", expected: true},
+		{value: "admin@example.com", expected: true},
+		{value: "Admin@Example.com", expected: true},
+		{value: "rooted", expected: false},
+		{value: "jane.admin@example.com", expected: false},
+		{value: "John Doe", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, reserved.Match(tc.value))
+		})
+	}
+}
+
+func TestCreateUserReservedNames(t *testing.T) {
+	reserved := NewReservedNames([]string{"root", "admin@*"})
+
+	testCases := []struct {
+		name          string
+		input         model.UserCreate
+		expectedField string
+	}{
+		{name: "ReservedExactName", input: model.UserCreate{Name: "Root", Email: "ops@example.com", Password: "password123"}, expectedField: "name"},
+		{name: "ReservedEmailPrefix", input: model.UserCreate{Name: "Jane", Email: "admin@example.com", Password: "password123"}, expectedField: "email"},
+		{name: "Allowed", input: model.UserCreate{Name: "Jane", Email: "jane@example.com", Password: "password123"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockRoleRepo := new(MockRoleRepository)
+			mockRoleRepo.On("Exists", mock.Anything, "user").Return(true, nil).Maybe()
+			service := NewUserService(mockRepo, mockRoleRepo, WithReservedNames(reserved))
+
+			// Execute
+			result, err := service.CreateUser(context.Background(), tc.input)
+
+			// Assert
+			if tc.expectedField == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.input.Email, result.Email)
+				return
+			}
+			var appErr *apperrors.AppError
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperrors.ErrCodeInvalidInput, appErr.Code)
+			assert.Equal(t, map[string]interface{}{"field": tc.expectedField}, appErr.Details)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateUserReservedEmail(t *testing.T) {
+	// Setup
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.User{ID: 1, Name: "Jane", Email: "jane@example.com"}, nil)
+	service := NewUserService(mockRepo, new(MockRoleRepository), WithReservedNames(NewReservedNames([]string{"admin@*"})))
+
+	// Execute
+	email := "admin@example.com"
+	result, err := service.UpdateUser(context.Background(), 1, model.UserUpdate{Email: &email})
+
+	// Assert
+	var appErr *apperrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.ErrCodeInvalidInput, appErr.Code)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/repository"
+)
+
+// transactionalUserService decorates a UserService so that its mutating methods run in a transaction
+type transactionalUserService struct {
+	UserService
+	transactor repository.Transactor
+}
+
+// NewTransactionalUserService wraps the mutating methods of next in a transaction,
+// committing on success and rolling back on error or panic
+func NewTransactionalUserService(next UserService, transactor repository.Transactor) UserService {
+	return &transactionalUserService{
+		UserService: next,
+		transactor:  transactor,
+	}
+}
+
+// CreateUser creates a new user within a transaction
+func (s *transactionalUserService) CreateUser(ctx context.Context, input model.UserCreate) (*model.UserResponse, error) {
+	var response *model.UserResponse
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = s.UserService.CreateUser(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// UpdateUser updates a user within a transaction
+func (s *transactionalUserService) UpdateUser(ctx context.Context, id uint, input model.UserUpdate) (*model.UserResponse, error) {
+	var response *model.UserResponse
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = s.UserService.UpdateUser(ctx, id, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeleteUser deletes a user within a transaction
+func (s *transactionalUserService) DeleteUser(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.UserService.DeleteUser(ctx, id)
+	})
+}
+
+// BulkUpdateStatus updates the status of several users within a transaction
+func (s *transactionalUserService) BulkUpdateStatus(ctx context.Context, input model.UserBulkStatusUpdate) (*model.BulkResult, error) {
+	var result *model.BulkResult
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.UserService.BulkUpdateStatus(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ChangeUserRole changes a user's role within a transaction, so the last-admin check commits with the update
+func (s *transactionalUserService) ChangeUserRole(ctx context.Context, id uint, newRole string, actorID string) (*model.UserResponse, error) {
+	var response *model.UserResponse
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = s.UserService.ChangeUserRole(ctx, id, newRole, actorID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
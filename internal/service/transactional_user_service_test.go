@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/ladderseeker/gin-crud-starter/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// writeThenFailUserService writes a user and then fails, to exercise rollback
+type writeThenFailUserService struct {
+	UserService
+	userRepo repository.UserRepository
+	panics   bool
+}
+
+func (s *writeThenFailUserService) CreateUser(ctx context.Context, input model.UserCreate) (*model.UserResponse, error) {
+	if err := s.userRepo.Create(ctx, &model.User{Name: input.Name, Email: input.Email, Password: input.Password}); err != nil {
+		return nil, err
+	}
+	if s.panics {
+		panic("boom")
+	}
+	return nil, errors.New("failed after write")
+}
+
+func newTransactionTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return db
+}
+
+func TestTransactionalUserServiceRollback(t *testing.T) {
+	testCases := []struct {
+		name   string
+		panics bool
+	}{
+		{name: "ReturnedError", panics: false},
+		{name: "Panic", panics: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTransactionTestDB(t)
+			inner := &writeThenFailUserService{userRepo: repository.NewUserRepository(db), panics: tc.panics}
+			service := NewTransactionalUserService(inner, repository.NewTransactor(db))
+
+			input := model.UserCreate{Name: "New User", Email: "newuser@example.com", Password: "password123"}
+			create := func() { _, _ = service.CreateUser(context.Background(), input) }
+
+			// Call the decorated method
+			if tc.panics {
+				assert.Panics(t, create)
+			} else {
+				_, err := service.CreateUser(context.Background(), input)
+				assert.Error(t, err)
+			}
+
+			// Assert the write was rolled back
+			var count int64
+			assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+			assert.Equal(t, int64(0), count)
+		})
+	}
+}
+
+func TestTransactionalUserServiceCommit(t *testing.T) {
+	db := newTransactionTestDB(t)
+
+	service := NewTransactionalUserService(
+		NewUserService(repository.NewUserRepository(db), &staticRoleRepository{}),
+		repository.NewTransactor(db),
+	)
+
+	// Create user through the decorated service
+	result, err := service.CreateUser(context.Background(), model.UserCreate{
+		Name:     "New User",
+		Email:    "newuser@example.com",
+		Password: "password123",
+	})
+
+	// Assert the write was committed
+	assert.NoError(t, err)
+	assert.NotZero(t, result.ID)
+
+	var count int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// staticRoleRepository accepts every role
+type staticRoleRepository struct{}
+
+func (r *staticRoleRepository) Exists(context.Context, string) (bool, error) {
+	return true, nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ladderseeker/gin-crud-starter/internal/model"
 	"github.com/ladderseeker/gin-crud-starter/pkg/errors"
 	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"strings"
 	"time"
 
 	"github.com/ladderseeker/gin-crud-starter/internal/repository"
@@ -14,34 +15,51 @@ import (
 
 // UserService defines the interface for user service
 type UserService interface {
-	GetAllUsers(ctx context.Context) ([]model.UserResponse, error)
+	GetAllUsers(ctx context.Context, page model.Page) ([]model.UserResponse, error)
 	GetUserByID(ctx context.Context, id uint) (*model.UserResponse, error)
+	GetUserByIDIncludingDeleted(ctx context.Context, id uint) (*model.UserResponse, error)
 	CreateUser(ctx context.Context, input model.UserCreate) (*model.UserResponse, error)
 	UpdateUser(ctx context.Context, id uint, input model.UserUpdate) (*model.UserResponse, error)
 	DeleteUser(ctx context.Context, id uint) error
+	MustExist(ctx context.Context, id uint) error
+	BulkUpdateStatus(ctx context.Context, input model.UserBulkStatusUpdate) (*model.BulkResult, error)
+	LookupByEmails(ctx context.Context, input model.UserEmailLookup) (*model.UserEmailLookupResult, error)
+	GetSummary(ctx context.Context) (*model.UserSummary, error)
+	ChangeUserRole(ctx context.Context, id uint, newRole string, actorID string) (*model.UserResponse, error)
 }
 
 // userServiceImpl implements the UserService interface
 type userServiceImpl struct {
-	userRepo repository.UserRepository
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	mxResolver MXResolver
+	mxTimeout  time.Duration
+	reserved   *ReservedNames
+
+	normalizeNames bool
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userServiceImpl{
+func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, opts ...UserServiceOption) UserService {
+	s := &userServiceImpl{
 		userRepo: userRepo,
+		roleRepo: roleRepo,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// GetAllUsers retrieves all users
-func (s *userServiceImpl) GetAllUsers(ctx context.Context) ([]model.UserResponse, error) {
+// GetAllUsers retrieves the users within page
+func (s *userServiceImpl) GetAllUsers(ctx context.Context, page model.Page) ([]model.UserResponse, error) {
 	// Add timeout to context
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	users, err := s.userRepo.FindAll(ctx)
+	users, err := s.userRepo.FindAll(ctx, page)
 	if err != nil {
-		logger.Error("Failed to get all users", zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to get all users", zap.Error(err))
 		return nil, err
 	}
 
@@ -62,7 +80,23 @@ func (s *userServiceImpl) GetUserByID(ctx context.Context, id uint) (*model.User
 
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error("Failed to get user by ID", zap.Uint("id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to get user by ID", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// GetUserByIDIncludingDeleted retrieves a user by ID, including soft-deleted users
+func (s *userServiceImpl) GetUserByIDIncludingDeleted(ctx context.Context, id uint) (*model.UserResponse, error) {
+	// Add timeout to context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user, err := s.userRepo.FindByIDUnscoped(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to get user by ID including deleted", zap.Uint("id", id), zap.Error(err))
 		return nil, err
 	}
 
@@ -76,32 +110,50 @@ func (s *userServiceImpl) CreateUser(ctx context.Context, input model.UserCreate
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	// Normalize the name, then reject reserved names and emails
+	name, err := s.normalizeName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkReserved("name", name); err != nil {
+		return nil, err
+	}
+	if err := s.checkReserved("email", input.Email); err != nil {
+		return nil, err
+	}
+
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
-		logger.Error("Failed to hash password", zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to hash password", zap.Error(err))
 		return nil, errors.NewInternalError("Failed to process password", err)
 	}
 
-	// Create user entity
+	// Create user entity, new users always get the default role and change it only through ChangeUserRole
 	user := &model.User{
-		Name:     input.Name,
+		Name:     name,
 		Email:    input.Email,
 		Password: string(hashedPassword),
-		Role:     input.Role,
+		Role:     model.RoleUser,
 		Active:   true,
 	}
 
-	// Default role if not provided
-	if user.Role == "" {
-		user.Role = "user"
+	// Ensure the default role exists
+	if err := s.validateRole(ctx, user.Role); err != nil {
+		return nil, err
+	}
+
+	// Validate the email domain can receive mail
+	if err := s.validateEmailDomain(ctx, user.Email); err != nil {
+		return nil, err
 	}
 
 	// Create user
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		logger.Error("Failed to create user", zap.String("email", input.Email), zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to create user", zap.String("email", input.Email), zap.Error(err))
 		return nil, err
 	}
+	logger.FromContext(ctx).Info("User created", zap.Uint("id", user.ID))
 
 	response := user.ToResponse()
 	return &response, nil
@@ -116,27 +168,41 @@ func (s *userServiceImpl) UpdateUser(ctx context.Context, id uint, input model.U
 	// Retrieve user
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error("Failed to retrieve user for update", zap.Uint("id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to retrieve user for update", zap.Uint("id", id), zap.Error(err))
 		return nil, err
 	}
 
 	// Update user fields if provided
 	if input.Name != nil {
-		user.Name = *input.Name
+		name, err := s.normalizeName(*input.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkReserved("name", name); err != nil {
+			return nil, err
+		}
+		user.Name = name
 	}
 	if input.Email != nil {
+		if err := s.checkReserved("email", *input.Email); err != nil {
+			return nil, err
+		}
 		user.Email = *input.Email
 	}
 	if input.Password != nil {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*input.Password), bcrypt.DefaultCost)
 		if err != nil {
-			logger.Error("Failed to hash password during update", zap.Error(err))
+			logger.FromContext(ctx).Error("Failed to hash password during update", zap.Error(err))
 			return nil, errors.NewInternalError("Failed to process password", err)
 		}
 		user.Password = string(hashedPassword)
 	}
-	if input.Role != nil {
-		user.Role = *input.Role
+
+	// Deactivating the last active admin would lock everyone out of admin actions
+	if input.Active != nil && !*input.Active && isActiveAdmin(user) {
+		if err := s.checkActiveAdminRemains(ctx, []uint{user.ID}, user.ID); err != nil {
+			return nil, err
+		}
 	}
 	if input.Active != nil {
 		user.Active = *input.Active
@@ -144,9 +210,10 @@ func (s *userServiceImpl) UpdateUser(ctx context.Context, id uint, input model.U
 
 	// Update user
 	if err := s.userRepo.Update(ctx, user); err != nil {
-		logger.Error("Failed to update user", zap.Uint("id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to update user", zap.Uint("id", id), zap.Error(err))
 		return nil, err
 	}
+	logger.FromContext(ctx).Info("User updated", zap.Uint("id", id))
 
 	response := user.ToResponse()
 	return &response, nil
@@ -158,11 +225,257 @@ func (s *userServiceImpl) DeleteUser(ctx context.Context, id uint) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	// Retrieve user
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Deleting the last active admin would lock everyone out of admin actions
+	if isActiveAdmin(user) {
+		if err := s.checkActiveAdminRemains(ctx, []uint{user.ID}, user.ID); err != nil {
+			return err
+		}
+	}
+
 	// Delete user
 	if err := s.userRepo.Delete(ctx, id); err != nil {
-		logger.Error("Failed to delete user", zap.Uint("id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("Failed to delete user", zap.Uint("id", id), zap.Error(err))
+		return err
+	}
+	logger.FromContext(ctx).Info("User deleted", zap.Uint("id", id))
+
+	return nil
+}
+
+// MustExist returns a not found error if the user doesn't exist
+func (s *userServiceImpl) MustExist(ctx context.Context, id uint) error {
+	exists, err := s.userRepo.Exists(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to check user existence", zap.Uint("id", id), zap.Error(err))
+		return err
+	}
+	if !exists {
+		return errors.NewResourceNotFoundError("User not found", map[string]interface{}{"id": id}, nil)
+	}
+	return nil
+}
+
+// BulkUpdateStatus activates or deactivates several users at once
+func (s *userServiceImpl) BulkUpdateStatus(ctx context.Context, input model.UserBulkStatusUpdate) (*model.BulkResult, error) {
+	// Add timeout to context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if len(input.UserIDs) == 0 {
+		return nil, errors.NewInvalidInputError("At least one user ID is required", nil, nil)
+	}
+
+	// Keep at least one active admin when deactivating
+	if !*input.Active {
+		if err := s.checkActiveAdminRemains(ctx, input.UserIDs, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update users
+	updatedIDs, err := s.userRepo.UpdateActiveStatus(ctx, input.UserIDs, *input.Active)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to bulk update user status", zap.Int("count", len(input.UserIDs)), zap.Error(err))
+		return nil, err
+	}
+
+	// Report updated IDs, then requested IDs that don't exist
+	result := model.NewBulkResult()
+	reported := make(map[uint]bool, len(input.UserIDs))
+	for _, id := range updatedIDs {
+		result.Succeed(id)
+		reported[id] = true
+	}
+	for _, id := range input.UserIDs {
+		if !reported[id] {
+			result.Missing(id)
+			reported[id] = true // report each missing ID once
+		}
+	}
+
+	logger.FromContext(ctx).Info("Bulk updated user status",
+		zap.Bool("active", *input.Active),
+		zap.Int("updated", len(result.Succeeded)),
+		zap.Int("not_found", len(result.NotFound)))
+
+	return result, nil
+}
+
+// LookupByEmails resolves several emails to users in one query, matching case-insensitively
+func (s *userServiceImpl) LookupByEmails(ctx context.Context, input model.UserEmailLookup) (*model.UserEmailLookupResult, error) {
+	// Add timeout to context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if len(input.Emails) > model.MaxEmailLookup {
+		return nil, errors.NewInvalidInputError("Too many emails", map[string]interface{}{"max": model.MaxEmailLookup}, nil)
+	}
+
+	// Normalize and deduplicate emails, keeping request order
+	emails := make([]string, 0, len(input.Emails))
+	seen := make(map[string]bool, len(input.Emails))
+	for _, email := range input.Emails {
+		normalized := strings.ToLower(strings.TrimSpace(email))
+		if !seen[normalized] {
+			seen[normalized] = true
+			emails = append(emails, normalized)
+		}
+	}
+
+	users, err := s.userRepo.FindByEmails(ctx, emails)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to look up users by email", zap.Int("count", len(emails)), zap.Error(err))
+		return nil, err
+	}
+
+	// Report emails without a matching user
+	result := &model.UserEmailLookupResult{
+		Users:    make([]model.UserResponse, 0, len(users)),
+		NotFound: []string{},
+	}
+	found := make(map[string]bool, len(users))
+	for _, user := range users {
+		result.Users = append(result.Users, user.ToResponse())
+		found[strings.ToLower(user.Email)] = true
+	}
+	for _, email := range emails {
+		if !found[email] {
+			result.NotFound = append(result.NotFound, email)
+		}
+	}
+
+	return result, nil
+}
+
+// GetSummary counts users by role and by active status
+func (s *userServiceImpl) GetSummary(ctx context.Context) (*model.UserSummary, error) {
+	// Add timeout to context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	counts, err := s.userRepo.CountByRoleAndStatus(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to count users", zap.Error(err))
+		return nil, err
+	}
+
+	// Fold the role and status groups into both breakdowns
+	summary := &model.UserSummary{ByRole: map[string]int64{}}
+	for _, count := range counts {
+		summary.Total += count.Count
+		summary.ByRole[count.Role] += count.Count
+		if count.Active {
+			summary.Active += count.Count
+		} else {
+			summary.Inactive += count.Count
+		}
+	}
+
+	return summary, nil
+}
+
+// ChangeUserRole moves a user to newRole on behalf of actorID, recording the change in the audit log.
+// The role must exist and the last active admin can't be moved out of the admin role.
+func (s *userServiceImpl) ChangeUserRole(ctx context.Context, id uint, newRole string, actorID string) (*model.UserResponse, error) {
+	// Add timeout to context
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	ctx = logger.WithActorID(ctx, actorID)
+
+	// Retrieve user
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to retrieve user for role change", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.checkRoleChange(ctx, user, newRole); err != nil {
+		return nil, err
+	}
+
+	// Update user
+	oldRole := user.Role
+	user.Role = newRole
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).Error("Failed to change user role", zap.Uint("id", id), zap.Error(err))
+		return nil, err
+	}
+	logger.FromContext(ctx).Info("User role changed",
+		zap.String("audit", auditRoleChanged),
+		zap.Uint("id", id),
+		zap.String("old_role", oldRole),
+		zap.String("new_role", newRole))
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// auditRoleChanged tags the audit log entry written for every role change
+const auditRoleChanged = "user.role_changed"
+
+// checkRoleChange validates newRole and refuses to demote the last active admin
+func (s *userServiceImpl) checkRoleChange(ctx context.Context, user *model.User, newRole string) error {
+	if err := s.validateRole(ctx, newRole); err != nil {
 		return err
 	}
+	if !isActiveAdmin(user) || newRole == model.RoleAdmin {
+		return nil
+	}
+	return s.checkActiveAdminRemains(ctx, []uint{user.ID}, user.ID)
+}
+
+// checkActiveAdminRemains refuses to demote, deactivate or delete the users in removing when no active admin would be left.
+// The active admins stay locked until the caller's transaction ends, so the check holds when the change is written.
+// id names the affected user in the error, zero for a batch.
+func (s *userServiceImpl) checkActiveAdminRemains(ctx context.Context, removing []uint, id uint) error {
+	// Lock the active admins, including those being removed
+	admins, err := s.userRepo.LockActiveIDsInRole(ctx, model.RoleAdmin)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to lock admins", zap.Error(err))
+		return err
+	}
+	removed := make(map[uint]bool, len(removing))
+	for _, userID := range removing {
+		removed[userID] = true
+	}
+	remaining := 0
+	for _, adminID := range admins {
+		if !removed[adminID] {
+			remaining++
+		}
+	}
+	// Allow the change while an active admin remains or when it affects none of them
+	if remaining >= 1 || remaining == len(admins) {
+		return nil
+	}
 
+	var details map[string]interface{}
+	if id != 0 {
+		details = map[string]interface{}{"id": id}
+	}
+	return errors.NewConflictError("Cannot demote, deactivate or delete the last active admin", details, nil)
+}
+
+// isActiveAdmin reports whether user currently counts towards the active admins
+func isActiveAdmin(user *model.User) bool {
+	return user.Role == model.RoleAdmin && user.Active
+}
+
+// validateRole checks that the role exists
+func (s *userServiceImpl) validateRole(ctx context.Context, role string) error {
+	exists, err := s.roleRepo.Exists(ctx, role)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to validate role", zap.String("role", role), zap.Error(err))
+		return err
+	}
+	if !exists {
+		return errors.NewInvalidInputError("Unknown role", map[string]interface{}{"role": role}, nil)
+	}
 	return nil
 }
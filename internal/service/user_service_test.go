@@ -5,10 +5,14 @@ import (
 	"errors"
 	"github.com/ladderseeker/gin-crud-starter/internal/model"
 	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 // MockUserRepository is a mock implementation of repository.UserRepository
@@ -16,11 +20,27 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) FindAll(ctx context.Context) ([]model.User, error) {
-	args := m.Called(ctx)
+func (m *MockUserRepository) FindAll(ctx context.Context, page model.Page) ([]model.User, error) {
+	args := m.Called(ctx, page)
 	return args.Get(0).([]model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) LockActiveIDsInRole(ctx context.Context, role string) ([]uint, error) {
+	args := m.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockUserRepository) CountByRoleAndStatus(ctx context.Context) ([]model.UserRoleStatusCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.UserRoleStatusCount), args.Error(1)
+}
+
 func (m *MockUserRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -29,6 +49,22 @@ func (m *MockUserRepository) FindByID(ctx context.Context, id uint) (*model.User
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByIDUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmails(ctx context.Context, emails []string) ([]model.User, error) {
+	args := m.Called(ctx, emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
@@ -37,6 +73,11 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) Exists(ctx context.Context, id uint) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
@@ -52,6 +93,24 @@ func (m *MockUserRepository) Delete(ctx context.Context, id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateActiveStatus(ctx context.Context, ids []uint, active bool) ([]uint, error) {
+	args := m.Called(ctx, ids, active)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+// MockRoleRepository is a mock implementation of repository.RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Exists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestGetAllUsers(t *testing.T) {
 	// Create mock repository
 	mockRepo := new(MockUserRepository)
@@ -63,13 +122,13 @@ func TestGetAllUsers(t *testing.T) {
 	}
 
 	// Set expectations
-	mockRepo.On("FindAll", mock.Anything).Return(users, nil)
+	mockRepo.On("FindAll", mock.Anything, model.Page{}).Return(users, nil)
 
 	// Create service with mock repository
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, new(MockRoleRepository))
 
 	// Call the service method
-	result, err := service.GetAllUsers(context.Background())
+	result, err := service.GetAllUsers(context.Background(), model.Page{})
 
 	// Assert results
 	assert.NoError(t, err)
@@ -131,7 +190,7 @@ func TestGetUserByID(t *testing.T) {
 			mockRepo.On("FindByID", mock.Anything, tc.id).Return(tc.mockReturn, tc.mockError)
 
 			// Create service with mock repository
-			service := NewUserService(mockRepo)
+			service := NewUserService(mockRepo, new(MockRoleRepository))
 
 			// Call the service method
 			result, err := service.GetUserByID(context.Background(), tc.id)
@@ -162,7 +221,6 @@ func TestCreateUser(t *testing.T) {
 		Name:     "New User",
 		Email:    "newuser@example.com",
 		Password: "password123",
-		Role:     "user",
 	}
 
 	// Capture the created user for validation
@@ -172,8 +230,12 @@ func TestCreateUser(t *testing.T) {
 		return u.Name == userInput.Name && u.Email == userInput.Email
 	})).Return(nil)
 
+	// Role exists
+	mockRoleRepo := new(MockRoleRepository)
+	mockRoleRepo.On("Exists", mock.Anything, "user").Return(true, nil)
+
 	// Create service with mock repository
-	service := NewUserService(mockRepo)
+	service := NewUserService(mockRepo, mockRoleRepo)
 
 	// Call the service method
 	result, err := service.CreateUser(context.Background(), userInput)
@@ -184,33 +246,424 @@ func TestCreateUser(t *testing.T) {
 	assert.Equal(t, userInput.Name, result.Name)
 	assert.Equal(t, userInput.Email, result.Email)
 
-	// Assert password was hashed (not stored as plaintext)
+	// Assert password was hashed (not stored as plaintext) and the default role assigned
 	assert.NotEqual(t, userInput.Password, capturedUser.Password)
+	assert.Equal(t, model.RoleUser, capturedUser.Role)
 
 	// Verify expectations
 	mockRepo.AssertExpectations(t)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserLastAdmin(t *testing.T) {
+	inactive := false
+
+	testCases := []struct {
+		name          string
+		user          model.User
+		update        model.UserUpdate
+		activeAdmins  []uint
+		expectedError bool
+		expectLocked  bool
+	}{
+		{name: "DeactivateUser", user: model.User{ID: 1, Role: model.RoleUser, Active: true}, update: model.UserUpdate{Active: &inactive}},
+		{name: "DeactivateOneOfSeveralAdmins", user: model.User{ID: 1, Role: model.RoleAdmin, Active: true}, update: model.UserUpdate{Active: &inactive}, activeAdmins: []uint{1, 2}, expectLocked: true},
+		{name: "DeactivateLastAdmin", user: model.User{ID: 1, Role: model.RoleAdmin, Active: true}, update: model.UserUpdate{Active: &inactive}, activeAdmins: []uint{1}, expectedError: true, expectLocked: true},
+		{name: "InactiveAdmin", user: model.User{ID: 1, Role: model.RoleAdmin, Active: false}, update: model.UserUpdate{Active: &inactive}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			user := tc.user
+			mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&user, nil)
+			mockRepo.On("LockActiveIDsInRole", mock.Anything, model.RoleAdmin).Return(tc.activeAdmins, nil).Maybe()
+			mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockRoleRepo := new(MockRoleRepository)
+			mockRoleRepo.On("Exists", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+			service := NewUserService(mockRepo, mockRoleRepo)
+
+			// Execute
+			result, err := service.UpdateUser(context.Background(), 1, tc.update)
+
+			// Assert
+			if tc.expectLocked {
+				mockRepo.AssertCalled(t, "LockActiveIDsInRole", mock.Anything, model.RoleAdmin)
+			} else {
+				mockRepo.AssertNotCalled(t, "LockActiveIDsInRole", mock.Anything, mock.Anything)
+			}
+			if tc.expectedError {
+				assert.Nil(t, result)
+				assert.Equal(t, apperrors.ErrCodeConflict, err.(*apperrors.AppError).Code)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestDeleteUser(t *testing.T) {
-	// Create mock repository
+	testCases := []struct {
+		name          string
+		user          *model.User
+		activeAdmins  []uint
+		expectedCode  string
+		expectDeleted bool
+	}{
+		{name: "User", user: &model.User{ID: 1, Role: model.RoleUser, Active: true}, expectDeleted: true},
+		{name: "OneOfSeveralAdmins", user: &model.User{ID: 1, Role: model.RoleAdmin, Active: true}, activeAdmins: []uint{1, 2}, expectDeleted: true},
+		{name: "LastAdmin", user: &model.User{ID: 1, Role: model.RoleAdmin, Active: true}, activeAdmins: []uint{1}, expectedCode: apperrors.ErrCodeConflict},
+		{name: "NotFound", expectedCode: apperrors.ErrCodeResourceNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			if tc.user != nil {
+				mockRepo.On("FindByID", mock.Anything, uint(1)).Return(tc.user, nil)
+			} else {
+				mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, apperrors.NewResourceNotFoundError("User not found", nil, nil))
+			}
+			mockRepo.On("LockActiveIDsInRole", mock.Anything, model.RoleAdmin).Return(tc.activeAdmins, nil).Maybe()
+			mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil).Maybe()
+			service := NewUserService(mockRepo, new(MockRoleRepository))
+
+			// Execute
+			err := service.DeleteUser(context.Background(), 1)
+
+			// Assert
+			if tc.expectedCode != "" {
+				assert.Equal(t, tc.expectedCode, err.(*apperrors.AppError).Code)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.expectDeleted {
+				mockRepo.AssertCalled(t, "Delete", mock.Anything, uint(1))
+			} else {
+				mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBulkUpdateStatus(t *testing.T) {
+	inactive := false
+
+	testCases := []struct {
+		name             string
+		ids              []uint
+		updatedIDs       []uint
+		expectedNotFound []uint
+	}{
+		{
+			name:             "AllFound",
+			ids:              []uint{1, 2, 3},
+			updatedIDs:       []uint{1, 2, 3},
+			expectedNotFound: []uint{},
+		},
+		{
+			name:             "SomeNotFound",
+			ids:              []uint{1, 4, 2, 5},
+			updatedIDs:       []uint{1, 2},
+			expectedNotFound: []uint{4, 5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a fresh mock for each test case
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("LockActiveIDsInRole", mock.Anything, model.RoleAdmin).Return([]uint{9}, nil)
+			mockRepo.On("UpdateActiveStatus", mock.Anything, tc.ids, false).Return(tc.updatedIDs, nil)
+
+			service := NewUserService(mockRepo, new(MockRoleRepository))
+
+			// Call the service method
+			result, err := service.BulkUpdateStatus(context.Background(), model.UserBulkStatusUpdate{UserIDs: tc.ids, Active: &inactive})
+
+			// Assert results
+			assert.NoError(t, err)
+			assert.Equal(t, tc.updatedIDs, result.Succeeded)
+			assert.Equal(t, tc.expectedNotFound, result.NotFound)
+			assert.Empty(t, result.Failed)
+			assert.Equal(t, len(tc.updatedIDs)+len(tc.expectedNotFound), result.Total)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBulkUpdateStatusLastAdmin(t *testing.T) {
+	inactive := false
+
+	testCases := []struct {
+		name          string
+		activeAdmins  []uint
+		expectedError bool
+	}{
+		{name: "NoAdmins", activeAdmins: []uint{}},
+		{name: "NoAdminsInBatch", activeAdmins: []uint{3}},
+		{name: "OtherAdminsRemain", activeAdmins: []uint{1, 3}},
+		{name: "LastAdmin", activeAdmins: []uint{1}, expectedError: true},
+		{name: "AllAdmins", activeAdmins: []uint{1, 2}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			ids := []uint{1, 2}
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("LockActiveIDsInRole", mock.Anything, model.RoleAdmin).Return(tc.activeAdmins, nil)
+			mockRepo.On("UpdateActiveStatus", mock.Anything, ids, false).Return(ids, nil).Maybe()
+			service := NewUserService(mockRepo, new(MockRoleRepository))
+
+			// Execute
+			result, err := service.BulkUpdateStatus(context.Background(), model.UserBulkStatusUpdate{UserIDs: ids, Active: &inactive})
+
+			// Assert
+			if tc.expectedError {
+				assert.Nil(t, result)
+				assert.Equal(t, apperrors.ErrCodeConflict, err.(*apperrors.AppError).Code)
+				mockRepo.AssertNotCalled(t, "UpdateActiveStatus", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, ids, result.Succeeded)
+		})
+	}
+}
+
+func TestBulkUpdateStatusActivateSkipsAdminCheck(t *testing.T) {
+	active := true
+	ids := []uint{1, 2}
 	mockRepo := new(MockUserRepository)
+	mockRepo.On("UpdateActiveStatus", mock.Anything, ids, true).Return(ids, nil)
+	service := NewUserService(mockRepo, new(MockRoleRepository))
 
-	// Set expectations
-	mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
-	mockRepo.On("Delete", mock.Anything, uint(2)).Return(apperrors.NewResourceNotFoundError("User not found", nil, nil))
+	result, err := service.BulkUpdateStatus(context.Background(), model.UserBulkStatusUpdate{UserIDs: ids, Active: &active})
 
-	// Create service with mock repository
-	service := NewUserService(mockRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, ids, result.Succeeded)
+	mockRepo.AssertNotCalled(t, "LockActiveIDsInRole", mock.Anything, mock.Anything)
+}
+
+func TestBulkUpdateStatusEmptyList(t *testing.T) {
+	inactive := false
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockRoleRepository))
+
+	result, err := service.BulkUpdateStatus(context.Background(), model.UserBulkStatusUpdate{UserIDs: []uint{}, Active: &inactive})
+
+	assert.Nil(t, result)
+	assert.Equal(t, apperrors.ErrCodeInvalidInput, err.(*apperrors.AppError).Code)
+	mockRepo.AssertNotCalled(t, "UpdateActiveStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMustExist(t *testing.T) {
+	testCases := []struct {
+		name          string
+		exists        bool
+		mockError     error
+		expectedCode  string
+		expectedError bool
+	}{
+		{name: "Exists", exists: true},
+		{name: "Missing", exists: false, expectedError: true, expectedCode: apperrors.ErrCodeResourceNotFound},
+		{name: "DatabaseError", mockError: apperrors.NewDatabaseError("Failed", nil), expectedError: true, expectedCode: apperrors.ErrCodeDatabase},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("Exists", mock.Anything, uint(1)).Return(tc.exists, tc.mockError)
+
+			service := NewUserService(mockRepo, new(MockRoleRepository))
+
+			err := service.MustExist(context.Background(), 1)
+
+			if tc.expectedError {
+				assert.Equal(t, tc.expectedCode, err.(*apperrors.AppError).Code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateUserLogsContextFields(t *testing.T) {
+	// Record log entries
+	core, logs := observer.New(zapcore.InfoLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
 
-	// Test successful deletion
-	err := service.DeleteUser(context.Background(), 1)
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockRoleRepo := new(MockRoleRepository)
+	mockRoleRepo.On("Exists", mock.Anything, "user").Return(true, nil)
+	service := NewUserService(mockRepo, mockRoleRepo)
+
+	// Create a user on behalf of a tenant and actor
+	ctx := logger.WithActorID(logger.WithTenantID(context.Background(), "tenant-1"), "42")
+	_, err := service.CreateUser(ctx, model.UserCreate{Name: "New User", Email: "newuser@example.com", Password: "password123"})
+
+	// Assert the mutation log line carries both fields
 	assert.NoError(t, err)
+	entries := logs.FilterMessage("User created").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "tenant-1", entries[0].ContextMap()["tenant_id"])
+	assert.Equal(t, "42", entries[0].ContextMap()["actor_id"])
+}
 
-	// Test deletion of non-existent user
-	err = service.DeleteUser(context.Background(), 2)
-	assert.Error(t, err)
-	assert.True(t, apperrors.IsNotFound(err))
+func TestChangeUserRoleWritesAuditEntry(t *testing.T) {
+	// Record log entries
+	core, logs := observer.New(zapcore.InfoLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
 
-	// Verify expectations
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&model.User{ID: 1, Role: model.RoleUser, Active: true}, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockRoleRepo := new(MockRoleRepository)
+	mockRoleRepo.On("Exists", mock.Anything, model.RoleAdmin).Return(true, nil)
+	service := NewUserService(mockRepo, mockRoleRepo)
+
+	// Promote a user on behalf of an admin
+	_, err := service.ChangeUserRole(context.Background(), 1, model.RoleAdmin, "admin:0123456789ab")
+
+	// Assert the audit entry names the actor and both roles
+	assert.NoError(t, err)
+	entries := logs.FilterField(zap.String("audit", auditRoleChanged)).All()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "admin:0123456789ab", fields["actor_id"])
+	assert.Equal(t, model.RoleUser, fields["old_role"])
+	assert.Equal(t, model.RoleAdmin, fields["new_role"])
+}
+
+func TestLookupByEmails(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+
+	// Emails are normalized and deduplicated before the single query
+	mockRepo.On("FindByEmails", mock.Anything, []string{"john@example.com", "missing@example.com", "jane@example.com"}).
+		Return([]model.User{
+			{ID: 1, Name: "John Doe", Email: "John@Example.com"},
+			{ID: 2, Name: "Jane Doe", Email: "jane@example.com"},
+		}, nil)
+
+	service := NewUserService(mockRepo, new(MockRoleRepository))
+
+	// Look up a mix of known and unknown emails
+	result, err := service.LookupByEmails(context.Background(), model.UserEmailLookup{
+		Emails: []string{" JOHN@example.com", "missing@example.com", "jane@example.com", "john@example.com"},
+	})
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.Len(t, result.Users, 2)
+	assert.Equal(t, uint(1), result.Users[0].ID)
+	assert.Equal(t, uint(2), result.Users[1].ID)
+	assert.Equal(t, []string{"missing@example.com"}, result.NotFound)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetSummary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		counts   []model.UserRoleStatusCount
+		expected *model.UserSummary
+	}{
+		{
+			name:     "Empty",
+			counts:   []model.UserRoleStatusCount{},
+			expected: &model.UserSummary{ByRole: map[string]int64{}},
+		},
+		{
+			name: "MixedRolesAndStatus",
+			counts: []model.UserRoleStatusCount{
+				{Role: model.RoleAdmin, Active: true, Count: 1},
+				{Role: model.RoleUser, Active: false, Count: 2},
+				{Role: model.RoleUser, Active: true, Count: 5},
+			},
+			expected: &model.UserSummary{
+				Total:    8,
+				Active:   6,
+				Inactive: 2,
+				ByRole:   map[string]int64{model.RoleAdmin: 1, model.RoleUser: 7},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("CountByRoleAndStatus", mock.Anything).Return(tc.counts, nil)
+			service := NewUserService(mockRepo, new(MockRoleRepository))
+
+			// Execute
+			summary, err := service.GetSummary(context.Background())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, summary)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChangeUserRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		user         model.User
+		newRole      string
+		roleExists   bool
+		activeAdmins []uint
+		expectedCode string
+		expectLocked bool
+	}{
+		{name: "PromoteUser", user: model.User{ID: 1, Role: model.RoleUser, Active: true}, newRole: model.RoleAdmin, roleExists: true},
+		{name: "DemoteOneOfSeveralAdmins", user: model.User{ID: 1, Role: model.RoleAdmin, Active: true}, newRole: model.RoleUser, roleExists: true, activeAdmins: []uint{1, 2}, expectLocked: true},
+		{name: "UnknownRole", user: model.User{ID: 1, Role: model.RoleUser, Active: true}, newRole: "superuser", expectedCode: apperrors.ErrCodeInvalidInput},
+		{name: "LastAdmin", user: model.User{ID: 1, Role: model.RoleAdmin, Active: true}, newRole: model.RoleUser, roleExists: true, activeAdmins: []uint{1}, expectedCode: apperrors.ErrCodeConflict, expectLocked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			user := tc.user
+			mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&user, nil)
+			mockRepo.On("LockActiveIDsInRole", mock.Anything, model.RoleAdmin).Return(tc.activeAdmins, nil).Maybe()
+			mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockRoleRepo := new(MockRoleRepository)
+			mockRoleRepo.On("Exists", mock.Anything, tc.newRole).Return(tc.roleExists, nil)
+			service := NewUserService(mockRepo, mockRoleRepo)
+
+			// Execute
+			result, err := service.ChangeUserRole(context.Background(), 1, tc.newRole, "admin:0123456789ab")
+
+			// Assert
+			if tc.expectLocked {
+				mockRepo.AssertCalled(t, "LockActiveIDsInRole", mock.Anything, model.RoleAdmin)
+			} else {
+				mockRepo.AssertNotCalled(t, "LockActiveIDsInRole", mock.Anything, mock.Anything)
+			}
+			if tc.expectedCode != "" {
+				var appErr *apperrors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tc.expectedCode, appErr.Code)
+				assert.Nil(t, result)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.newRole, result.Role)
+			mockRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package softlimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultRatio is the share of a hard limit at which the soft limit warns
+const DefaultRatio = 0.8
+
+// Usage reports the current use of a resource and its hard limit, a limit of zero or less is unbounded
+type Usage func() (used, limit int)
+
+// Monitor periodically compares resource usage with a soft threshold below each hard limit.
+// It only warns, enforcing the hard limits is left to the resources themselves.
+type Monitor struct {
+	ratio     float64
+	mu        sync.Mutex
+	resources []resource
+}
+
+type resource struct {
+	name    string
+	usage   Usage
+	crossed bool
+}
+
+// NewMonitor creates a monitor warning once a resource reaches ratio of its hard limit
+func NewMonitor(ratio float64) *Monitor {
+	if ratio <= 0 || ratio > 1 {
+		ratio = DefaultRatio
+	}
+	return &Monitor{ratio: ratio}
+}
+
+// Add registers a resource to watch under name
+func (m *Monitor) Add(name string, usage Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resources = append(m.resources, resource{name: name, usage: usage})
+}
+
+// Check samples every resource, warning when one crosses its soft limit and noting when it falls back below.
+// A resource stays quiet while it remains above the soft limit, so a sustained high load warns once.
+func (m *Monitor) Check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.resources {
+		r := &m.resources[i]
+		used, limit := r.usage()
+		if limit <= 0 {
+			continue
+		}
+
+		soft := int(float64(limit) * m.ratio)
+		switch {
+		case used >= soft && !r.crossed:
+			r.crossed = true
+			logger.Warn("Soft limit crossed",
+				zap.String("resource", r.name),
+				zap.Int("used", used),
+				zap.Int("soft_limit", soft),
+				zap.Int("hard_limit", limit),
+			)
+		case used < soft && r.crossed:
+			r.crossed = false
+			logger.Info("Usage back below soft limit",
+				zap.String("resource", r.name),
+				zap.Int("used", used),
+				zap.Int("soft_limit", soft),
+			)
+		}
+	}
+}
+
+// Run checks usage every interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
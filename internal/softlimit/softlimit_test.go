@@ -0,0 +1,67 @@
+package softlimit
+
+import (
+	"testing"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs captures log entries for the duration of the test
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.InfoLevel)
+	original := logger.Logger
+	logger.Logger = zap.New(core)
+	t.Cleanup(func() { logger.Logger = original })
+	return logs
+}
+
+func TestMonitorWarnsPastSoftLimit(t *testing.T) {
+	logs := observeLogs(t)
+	monitor := NewMonitor(0.8)
+
+	// Watch a pool of 10 connections
+	inUse := 0
+	monitor.Add("database_connections", func() (int, int) { return inUse, 10 })
+
+	// Below the soft limit nothing is logged
+	inUse = 7
+	monitor.Check()
+	assert.Equal(t, 0, logs.Len())
+
+	// Past the soft limit but below the hard cap it warns once
+	inUse = 9
+	monitor.Check()
+	monitor.Check()
+	warnings := logs.FilterMessage("Soft limit crossed").All()
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, zapcore.WarnLevel, warnings[0].Level)
+	assert.Equal(t, map[string]interface{}{
+		"resource":   "database_connections",
+		"used":       int64(9),
+		"soft_limit": int64(8),
+		"hard_limit": int64(10),
+	}, warnings[0].ContextMap())
+
+	// Falling back below resets it so the next crossing warns again
+	inUse = 5
+	monitor.Check()
+	assert.Equal(t, 1, logs.FilterMessage("Usage back below soft limit").Len())
+
+	inUse = 8
+	monitor.Check()
+	assert.Equal(t, 2, logs.FilterMessage("Soft limit crossed").Len())
+}
+
+func TestMonitorSkipsUnboundedResources(t *testing.T) {
+	logs := observeLogs(t)
+	monitor := NewMonitor(0.8)
+	monitor.Add("unbounded", func() (int, int) { return 100, 0 })
+
+	monitor.Check()
+
+	assert.Equal(t, 0, logs.Len())
+}
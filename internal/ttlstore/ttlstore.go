@@ -0,0 +1,143 @@
+// Package ttlstore provides a concurrency-safe keyed store whose entries expire,
+// for per-client state such as rate limit windows shared across request goroutines.
+package ttlstore
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// shardCount spreads keys over independent locks to reduce contention
+const shardCount = 16
+
+// Store maps string keys to values of type V, each expiring ttl after it was created
+type Store[V any] struct {
+	ttl    time.Duration
+	now    func() time.Time
+	shards [shardCount]*shard[V]
+}
+
+type shard[V any] struct {
+	mu        sync.Mutex
+	entries   map[string]entry[V]
+	nextSweep time.Time
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Option configures a Store
+type Option func(*options)
+
+type options struct {
+	now func() time.Time
+}
+
+// WithClock sets the clock used for expiry, for tests
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// New creates a store whose entries expire ttl after creation
+func New[V any](ttl time.Duration, opts ...Option) *Store[V] {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Store[V]{ttl: ttl, now: o.now}
+	for i := range s.shards {
+		s.shards[i] = &shard[V]{entries: make(map[string]entry[V])}
+	}
+	return s
+}
+
+// Get returns the value for key if it exists and hasn't expired
+func (s *Store[V]) Get(key string) (V, bool) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	e, ok := sh.entries[key]
+	if !ok || !s.now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key with a fresh expiry
+func (s *Store[V]) Set(key string, value V) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	now := s.now()
+	s.sweep(sh, now)
+	sh.entries[key] = entry[V]{value: value, expires: now.Add(s.ttl)}
+}
+
+// Update atomically replaces the value for key with the result of fn.
+// fn receives the current value and whether it exists; a live entry keeps its expiry, a new one starts a fresh ttl.
+func (s *Store[V]) Update(key string, fn func(value V, ok bool) V) V {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	now := s.now()
+	s.sweep(sh, now)
+
+	e, ok := sh.entries[key]
+	if !ok || !now.Before(e.expires) {
+		e = entry[V]{expires: now.Add(s.ttl)}
+		ok = false
+	}
+	e.value = fn(e.value, ok)
+	sh.entries[key] = e
+	return e.value
+}
+
+// Delete removes key
+func (s *Store[V]) Delete(key string) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	delete(sh.entries, key)
+}
+
+// Len returns the number of stored entries, including expired ones not yet evicted
+func (s *Store[V]) Len() int {
+	n := 0
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		n += len(sh.entries)
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// shard returns the shard owning key
+func (s *Store[V]) shard(key string) *shard[V] {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.shards[h.Sum32()%shardCount]
+}
+
+// sweep evicts the shard's expired entries at most once per ttl, the caller must hold its lock
+func (s *Store[V]) sweep(sh *shard[V], now time.Time) {
+	if now.Before(sh.nextSweep) {
+		return
+	}
+	for key, e := range sh.entries {
+		if !now.Before(e.expires) {
+			delete(sh.entries, key)
+		}
+	}
+	sh.nextSweep = now.Add(s.ttl)
+}
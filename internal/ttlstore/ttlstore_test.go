@@ -0,0 +1,100 @@
+package ttlstore
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreExpiry(t *testing.T) {
+	// Create store with a controllable clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	store := New[int](time.Minute, WithClock(clock))
+
+	// Entries are readable until they expire
+	store.Set("a", 1)
+	advance(30 * time.Second)
+	store.Set("b", 2)
+
+	value, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// Updating a live entry keeps its expiry
+	store.Update("a", func(v int, ok bool) int { return v + 1 })
+	advance(30 * time.Second)
+
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+	value, ok = store.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	// Updating an expired entry starts over
+	value = store.Update("a", func(v int, ok bool) int {
+		assert.False(t, ok)
+		return v + 10
+	})
+	assert.Equal(t, 10, value)
+
+	// Expired entries are evicted by later writes
+	for i := 0; i < 100; i++ {
+		store.Set(fmt.Sprintf("key-%d", i), i)
+	}
+	advance(2 * time.Minute)
+	for i := 0; i < 100; i++ {
+		store.Set(fmt.Sprintf("fresh-%d", i), i)
+	}
+	assert.Equal(t, 100, store.Len())
+
+	// Delete removes an entry
+	store.Delete("fresh-0")
+	_, ok = store.Get("fresh-0")
+	assert.False(t, ok)
+}
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	store := New[int](time.Minute)
+
+	// Hammer a few shared keys from many goroutines, run with -race to detect unsafe access
+	const goroutines = 64
+	const increments = 500
+	keys := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < increments; i++ {
+				key := keys[(g+i)%len(keys)]
+				store.Update(key, func(v int, _ bool) int { return v + 1 })
+				store.Get(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// Assert no increment was lost
+	total := 0
+	for _, key := range keys {
+		value, ok := store.Get(key)
+		assert.True(t, ok)
+		total += value
+	}
+	assert.Equal(t, goroutines*increments, total)
+}
@@ -0,0 +1,82 @@
+// Package validation registers custom validators usable in binding tags.
+//
+// Once Register has run, reference a validator by name like any built-in rule:
+//
+//	Password string `json:"password" binding:"required,strong_password"`
+//	Phone    string `json:"phone" binding:"omitempty,phone"`
+//	Slug     string `json:"slug" binding:"required,slug"`
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Custom validator tags
+const (
+	TagStrongPassword = "strong_password"
+	TagPhone          = "phone"
+	TagSlug           = "slug"
+)
+
+var (
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// validators maps each custom tag to its validation function
+var validators = map[string]validator.Func{
+	TagStrongPassword: strongPassword,
+	TagPhone:          phone,
+	TagSlug:           slug,
+}
+
+// Register adds the custom validators to the given validator engine,
+// typically gin's binding.Validator.Engine()
+func Register(engine any) error {
+	v, ok := engine.(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unsupported validator engine %T", engine)
+	}
+
+	for tag, fn := range validators {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("failed to register validator %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// strongPassword requires at least 8 characters mixing letters, digits and upper and lower case
+func strongPassword(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if len(value) < 8 {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for _, r := range value {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// phone accepts E.164 numbers such as +14155552671
+func phone(fl validator.FieldLevel) bool {
+	return phonePattern.MatchString(fl.Field().String())
+}
+
+// slug accepts lowercase words separated by single hyphens
+func slug(fl validator.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
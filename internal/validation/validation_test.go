@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+// signup is a DTO using the custom validators
+type signup struct {
+	Password string `json:"password" binding:"required,strong_password"`
+	Phone    string `json:"phone" binding:"omitempty,phone"`
+	Slug     string `json:"slug" binding:"omitempty,slug"`
+}
+
+func TestRegister(t *testing.T) {
+	assert.NoError(t, Register(binding.Validator.Engine()))
+
+	testCases := []struct {
+		name    string
+		body    string
+		isValid bool
+	}{
+		{name: "Valid", body: `{"password":"Secur3Pass","phone":"+14155552671","slug":"my-team"}`, isValid: true},
+		{name: "WeakPassword", body: `{"password":"password"}`, isValid: false},
+		{name: "ShortPassword", body: `{"password":"Ab1"}`, isValid: false},
+		{name: "InvalidPhone", body: `{"password":"Secur3Pass","phone":"555-2671"}`, isValid: false},
+		{name: "InvalidSlug", body: `{"password":"Secur3Pass","slug":"My Team"}`, isValid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var input signup
+			err := binding.JSON.BindBody([]byte(tc.body), &input)
+
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRegisterUnsupportedEngine(t *testing.T) {
+	assert.Error(t, Register(struct{}{}))
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ladderseeker/gin-crud-starter/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Worker is a background task that runs until its context is cancelled
+type Worker func(ctx context.Context) error
+
+// Group runs background workers with a shared context and drains them on shutdown
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	errs   []error
+}
+
+// NewGroup creates a worker group whose workers stop when parent is cancelled or the group shuts down
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go starts a named worker. Errors and panics are recorded and reported by Shutdown.
+func (g *Group) Go(name string, worker Worker) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				g.record(fmt.Errorf("worker %s panicked: %v", name, recovered))
+			}
+		}()
+
+		logger.Info("Worker started", zap.String("worker", name))
+		if err := worker(g.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			g.record(fmt.Errorf("worker %s: %w", name, err))
+		}
+		logger.Info("Worker stopped", zap.String("worker", name))
+	}()
+}
+
+// Shutdown cancels the workers and waits for them to finish, or until ctx is done
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("workers did not stop in time: %w", ctx.Err())
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+// record stores a worker failure
+func (g *Group) record(err error) {
+	logger.Error("Worker failed", zap.Error(err))
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupShutdownRunsCleanup(t *testing.T) {
+	group := NewGroup(context.Background())
+
+	// Register a worker that cleans up once cancelled
+	cleanedUp := false
+	started := make(chan struct{})
+	group.Go("flusher", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		cleanedUp = true
+		return ctx.Err()
+	})
+	<-started
+
+	// Shut down
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := group.Shutdown(ctx)
+
+	// Assert the worker drained before Shutdown returned
+	assert.NoError(t, err)
+	assert.True(t, cleanedUp)
+}
+
+func TestGroupShutdownTimeout(t *testing.T) {
+	group := NewGroup(context.Background())
+
+	// Register a worker that ignores cancellation
+	release := make(chan struct{})
+	defer close(release)
+	group.Go("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := group.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGroupShutdownReportsFailures(t *testing.T) {
+	group := NewGroup(context.Background())
+
+	group.Go("failing", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	group.Go("panicking", func(ctx context.Context) error {
+		panic("kaboom")
+	})
+
+	err := group.Shutdown(context.Background())
+
+	assert.ErrorContains(t, err, "worker failing: boom")
+	assert.ErrorContains(t, err, "worker panicking panicked: kaboom")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // AppError represents an application error
@@ -12,7 +13,11 @@ type AppError struct {
 	Code       string `json:"code"`
 	Message    string `json:"message"`
 	Details    any    `json:"details,omitempty"`
+	Retryable  bool   `json:"retryable"`
 	Err        error  `json:"-"`
+
+	// RetryAfter is how long clients should wait before retrying, sent as the Retry-After header
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements the error interface
@@ -33,10 +38,15 @@ const (
 	ErrCodeInvalidInput      = "INVALID_INPUT"
 	ErrCodeResourceNotFound  = "RESOURCE_NOT_FOUND"
 	ErrCodeDuplicateResource = "DUPLICATE_RESOURCE"
+	ErrCodeConflict          = "CONFLICT"
 	ErrCodeDatabase          = "DATABASE_ERROR"
 	ErrCodeInternal          = "INTERNAL_ERROR"
 	ErrCodeUnauthorized      = "UNAUTHORIZED"
 	ErrCodeForbidden         = "FORBIDDEN"
+	ErrCodeRateLimited       = "RATE_LIMITED"
+	ErrCodeTimeout           = "TIMEOUT"
+	ErrCodeUnavailable       = "SERVICE_UNAVAILABLE"
+	ErrCodeURITooLong        = "URI_TOO_LONG"
 )
 
 // New creates a new AppError
@@ -65,9 +75,14 @@ func NewDuplicateResourceError(message string, details any, err error) *AppError
 	return New(http.StatusConflict, ErrCodeDuplicateResource, message, details, err)
 }
 
-// NewDatabaseError creates a new database error
+// NewConflictError creates a new error for requests conflicting with the current state
+func NewConflictError(message string, details any, err error) *AppError {
+	return New(http.StatusConflict, ErrCodeConflict, message, details, err)
+}
+
+// NewDatabaseError creates a new database error, database failures are usually transient
 func NewDatabaseError(message string, err error) *AppError {
-	return New(http.StatusInternalServerError, ErrCodeDatabase, message, nil, err)
+	return retryable(New(http.StatusInternalServerError, ErrCodeDatabase, message, nil, err))
 }
 
 // NewInternalError creates a new internal server error
@@ -85,6 +100,33 @@ func NewForbiddenError(message string, err error) *AppError {
 	return New(http.StatusForbidden, ErrCodeForbidden, message, nil, err)
 }
 
+// NewRateLimitedError creates a new too many requests error, the request may be retried once the budget resets
+func NewRateLimitedError(message string, details any) *AppError {
+	return retryable(New(http.StatusTooManyRequests, ErrCodeRateLimited, message, details, nil))
+}
+
+// NewTimeoutError creates a new service unavailable error for requests that ran out of time
+func NewTimeoutError(message string, err error) *AppError {
+	return retryable(New(http.StatusServiceUnavailable, ErrCodeTimeout, message, nil, err))
+}
+
+// NewServiceUnavailableError creates a new service unavailable error for a dependency that is temporarily down
+func NewServiceUnavailableError(message string, err error) *AppError {
+	return retryable(New(http.StatusServiceUnavailable, ErrCodeUnavailable, message, nil, err))
+}
+
+// WithRetryAfter sets how long clients should wait before retrying
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	e.RetryAfter = d
+	return e
+}
+
+// retryable marks an error as transient so clients know the request may succeed if repeated
+func retryable(err *AppError) *AppError {
+	err.Retryable = true
+	return err
+}
+
 // IsNotFound checks if the error is a not found error
 func IsNotFound(err error) bool {
 	var appErr *AppError
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryable(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       *AppError
+		retryable bool
+	}{
+		{name: "Database", err: NewDatabaseError("Failed to fetch users", errors.New("connection reset")), retryable: true},
+		{name: "Timeout", err: NewTimeoutError("The request took too long to process", nil), retryable: true},
+		{name: "RateLimited", err: NewRateLimitedError("Too many requests", nil), retryable: true},
+		{name: "InvalidInput", err: NewInvalidInputError("Invalid input", nil, nil), retryable: false},
+		{name: "NotFound", err: NewResourceNotFoundError("User not found", nil, nil), retryable: false},
+		{name: "Duplicate", err: NewDuplicateResourceError("Email already in use", nil, nil), retryable: false},
+		{name: "Internal", err: NewInternalError("An unexpected error occurred", nil), retryable: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.retryable, tc.err.Retryable)
+
+			// Assert the flag is serialized
+			body, err := json.Marshal(tc.err)
+			assert.NoError(t, err)
+
+			var decoded map[string]any
+			assert.NoError(t, json.Unmarshal(body, &decoded))
+			assert.Equal(t, tc.retryable, decoded["retryable"])
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// fieldsKey stores request-scoped log fields on a context
+type fieldsKey struct{}
+
+// ContextWithFields returns a context whose logger adds fields to every line
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	existing, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	combined := make([]zap.Field, 0, len(existing)+len(fields))
+	combined = append(combined, existing...)
+	combined = append(combined, fields...)
+	return context.WithValue(ctx, fieldsKey{}, combined)
+}
+
+// WithTenantID tags the context's log lines with the tenant being served
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return ContextWithFields(ctx, zap.String("tenant_id", tenantID))
+}
+
+// WithActorID tags the context's log lines with the authenticated caller
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return ContextWithFields(ctx, zap.String("actor_id", actorID))
+}
+
+// FromContext returns the logger carrying the context's request-scoped fields
+func FromContext(ctx context.Context) *zap.Logger {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return GetLogger()
+	}
+	return GetLogger().With(fields...)
+}
@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"errors"
 	"os"
+	"syscall"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -78,3 +80,18 @@ func Debug(msg string, fields ...zap.Field) {
 func Warn(msg string, fields ...zap.Field) {
 	GetLogger().Warn(msg, fields...)
 }
+
+// SafeSync flushes the logger, ignoring the errors returned when stdout or stderr is a terminal or pipe
+// that doesn't support syncing, so only real flush failures are reported
+func SafeSync() error {
+	return safeSync(GetLogger())
+}
+
+// safeSync flushes logger, dropping benign sync errors
+func safeSync(logger *zap.Logger) error {
+	err := logger.Sync()
+	if err == nil || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingSyncer is a WriteSyncer whose Sync returns err
+type failingSyncer struct {
+	err error
+}
+
+func (s failingSyncer) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (s failingSyncer) Sync() error {
+	return s.err
+}
+
+func TestSafeSync(t *testing.T) {
+	errDisk := errors.New("disk full")
+
+	testCases := []struct {
+		name        string
+		syncErr     error
+		expectedErr error
+	}{
+		{name: "Success", syncErr: nil, expectedErr: nil},
+		{name: "StdoutInvalidArgument", syncErr: &fs.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL}, expectedErr: nil},
+		{name: "StdoutNotATerminal", syncErr: &fs.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.ENOTTY}, expectedErr: nil},
+		{name: "RealError", syncErr: errDisk, expectedErr: errDisk},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), failingSyncer{err: tc.syncErr}, zapcore.InfoLevel)
+			err := safeSync(zap.New(core))
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// maxCollectedQueries bounds the number of statements kept per request
+const maxCollectedQueries = 100
+
+type sqlCollectorKey struct{}
+
+// SQLCollector gathers the SQL statements executed while handling a request
+type SQLCollector struct {
+	mu      sync.Mutex
+	queries []string
+	dropped int
+}
+
+// ContextWithSQLCollector returns a context that collects executed SQL into a new collector
+func ContextWithSQLCollector(ctx context.Context) (context.Context, *SQLCollector) {
+	collector := &SQLCollector{}
+	return context.WithValue(ctx, sqlCollectorKey{}, collector), collector
+}
+
+// SQLCollectorFromContext returns the collector attached to the context, if any
+func SQLCollectorFromContext(ctx context.Context) *SQLCollector {
+	collector, _ := ctx.Value(sqlCollectorKey{}).(*SQLCollector)
+	return collector
+}
+
+// Add records an executed statement
+func (c *SQLCollector) Add(sql string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.queries) >= maxCollectedQueries {
+		c.dropped++
+		return
+	}
+	c.queries = append(c.queries, sql)
+}
+
+// Queries returns the recorded statements and how many were dropped over the limit
+func (c *SQLCollector) Queries() ([]string, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.queries...), c.dropped
+}
@@ -0,0 +1,27 @@
+package response
+
+import "net/http"
+
+// jsonRender writes Data with the encoder selected at build time
+type jsonRender struct {
+	Data any
+}
+
+// Render writes the JSON encoding of Data, like gin's render.JSON
+func (r jsonRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	data, err := marshalJSON(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteContentType sets the JSON content type unless the handler already set one
+func (r jsonRender) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if len(header["Content-Type"]) == 0 {
+		header["Content-Type"] = []string{"application/json; charset=utf-8"}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build jsoniter
+
+package response
+
+import jsoniter "github.com/json-iterator/go"
+
+// Encoder names the JSON library responses are encoded with
+const Encoder = "json-iterator"
+
+// marshalJSON encodes response bodies with json-iterator, configured to match encoding/json byte for byte.
+// The jsoniter tag also switches gin's own JSON rendering.
+var marshalJSON = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal
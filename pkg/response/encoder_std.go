@@ -0,0 +1,11 @@
+//go:build !jsoniter
+
+package response
+
+import "encoding/json"
+
+// Encoder names the JSON library responses are encoded with
+const Encoder = "encoding/json"
+
+// marshalJSON encodes response bodies, build with -tags jsoniter to switch to json-iterator
+var marshalJSON = json.Marshal
@@ -0,0 +1,85 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// representativePayloads covers struct tags, omitempty, nested values, maps and escaping
+func representativePayloads() map[string]any {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	deleted := model.NewTimestamp(created.Add(time.Hour))
+	users := []model.UserResponse{
+		{ID: 1, Name: "Jane <Doe> & Co", Email: "jane@example.com", Role: "admin", Active: true, CreatedAt: model.NewTimestamp(created), UpdatedAt: model.NewTimestamp(created)},
+		{ID: 2, Name: "José  ", Email: "jose@example.com", Role: "user", CreatedAt: model.NewTimestamp(created), UpdatedAt: model.NewTimestamp(created), DeletedAt: &deleted},
+	}
+	bulk := model.NewBulkResult()
+	bulk.Succeed(1)
+	bulk.Fail(2, "locked")
+	bulk.Missing(3)
+
+	return map[string]any{
+		"Users":   users,
+		"Error":   apperrors.NewInvalidInputError("Invalid input", map[string]interface{}{"field": "email", "z": 1.5, "a": nil}, nil),
+		"Bulk":    bulk,
+		"Summary": model.UserSummary{Total: 3, Active: 2, Inactive: 1, ByRole: map[string]int64{"user": 2, "admin": 1}},
+		"Empty":   []model.UserResponse{},
+		"Nil":     nil,
+	}
+}
+
+func TestEncoderMatchesStandardLibrary(t *testing.T) {
+	for name, payload := range representativePayloads() {
+		t.Run(name, func(t *testing.T) {
+			expected, err := json.Marshal(payload)
+			assert.NoError(t, err)
+
+			actual, err := marshalJSON(payload)
+
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual), "encoder %s", Encoder)
+		})
+	}
+}
+
+func TestJSONMatchesGinRender(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	payload := representativePayloads()["Users"]
+
+	// Render through gin and through the response helper
+	expected := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(expected)
+	ctx.JSON(http.StatusOK, payload)
+
+	actual := httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(actual)
+	JSON(ctx, http.StatusOK, payload)
+
+	// Assert identical responses
+	assert.Equal(t, expected.Code, actual.Code)
+	assert.Equal(t, expected.Header().Get("Content-Type"), actual.Header().Get("Content-Type"))
+	assert.Equal(t, expected.Body.String(), actual.Body.String())
+}
+
+func BenchmarkMarshalUserList(b *testing.B) {
+	created := model.NewTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	users := make([]model.UserResponse, 100)
+	for i := range users {
+		users[i] = model.UserResponse{ID: uint(i + 1), Name: "User", Email: "user@example.com", Role: "user", Active: true, CreatedAt: created, UpdatedAt: created}
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := marshalJSON(users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// Supported JSON key conventions
+const (
+	KeyCaseDefault = ""
+	KeyCaseSnake   = "snake"
+	KeyCaseCamel   = "camel"
+)
+
+// keyCase is the convention applied to response keys
+var keyCase = KeyCaseDefault
+
+// SetKeyCase sets the convention applied to response keys.
+// KeyCaseDefault keeps the struct tags as they are.
+func SetKeyCase(c string) {
+	keyCase = c
+}
+
+// Per-request opt-in to omitting empty values, via the query parameter or a Prefer header value
+const (
+	QueryOmitEmpty  = "omit_empty"
+	PreferOmitEmpty = "omit-empty"
+)
+
+// Send writes obj as the response body in the format negotiated from the Accept header.
+// MessagePack is used when the client asks for it, JSON otherwise.
+func Send(ctx *gin.Context, status int, obj any) {
+	switch ctx.NegotiateFormat(binding.MIMEJSON, binding.MIMEMSGPACK, binding.MIMEMSGPACK2) {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		ctx.Render(status, render.MsgPack{Data: msgpackPayload(ctx, obj)})
+	default:
+		JSON(ctx, status, obj)
+	}
+}
+
+// JSON writes obj as the response body, applying the configured key convention
+func JSON(ctx *gin.Context, status int, obj any) {
+	ctx.Render(status, jsonRender{Data: applyKeyCase(ctx, obj)})
+}
+
+// applyKeyCase rewrites obj to the configured key convention, dropping empty values if the request asks to,
+// falling back to obj on failure
+func applyKeyCase(ctx *gin.Context, obj any) any {
+	omit := wantsOmitEmpty(ctx)
+	if keyCase == KeyCaseDefault && !omit {
+		return obj
+	}
+
+	transformed, err := Transform(obj, keyCase)
+	if err != nil {
+		_ = ctx.Error(err)
+		return obj
+	}
+	if omit {
+		transformed = OmitEmpty(transformed)
+	}
+	return transformed
+}
+
+// msgpackPayload converts obj to generic values before MessagePack encoding, so types with a JSON encoding
+// such as model.Timestamp are sent as they are in JSON rather than in their Go binary form
+func msgpackPayload(ctx *gin.Context, obj any) any {
+	transformed, err := Transform(obj, keyCase)
+	if err != nil {
+		_ = ctx.Error(err)
+		return obj
+	}
+	if wantsOmitEmpty(ctx) {
+		transformed = OmitEmpty(transformed)
+	}
+	return decodeNumbers(transformed)
+}
+
+// decodeNumbers recursively replaces json.Number values with int64, or float64 when not integral,
+// so MessagePack encodes them as numbers rather than strings
+func decodeNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = decodeNumbers(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = decodeNumbers(item)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// wantsOmitEmpty reports whether the request opted into omitting empty values
+func wantsOmitEmpty(ctx *gin.Context) bool {
+	if ctx.Request == nil {
+		return false
+	}
+	if omit, err := strconv.ParseBool(ctx.Query(QueryOmitEmpty)); err == nil {
+		return omit
+	}
+	for _, prefer := range ctx.Request.Header.Values("Prefer") {
+		for _, token := range strings.Split(prefer, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), PreferOmitEmpty) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OmitEmpty recursively removes object fields holding null, false, zero, an empty string, or an empty array or object,
+// mirroring the omitempty struct tag. Array elements are kept so positions don't shift.
+func OmitEmpty(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			item = OmitEmpty(item)
+			if !isEmptyValue(item) {
+				result[key] = item
+			}
+		}
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = OmitEmpty(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isEmptyValue reports whether a generic JSON value is empty under omitempty semantics
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f == 0
+	case float64:
+		return v == 0
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// Transform converts obj into a generic JSON value with its object keys rewritten to the given convention
+func Transform(obj any, convention string) (any, error) {
+	data, err := marshalJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	switch convention {
+	case KeyCaseSnake:
+		return rewriteKeys(value, toSnakeCase), nil
+	case KeyCaseCamel:
+		return rewriteKeys(value, toCamelCase), nil
+	default:
+		return value, nil
+	}
+}
+
+// rewriteKeys recursively renames the keys of every object in value
+func rewriteKeys(value any, rename func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[rename(key)] = rewriteKeys(item, rename)
+		}
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = rewriteKeys(item, rename)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// toSnakeCase converts camelCase or PascalCase to snake_case
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word after a lowercase letter or at the end of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && runes[i-1] != '_')) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toCamelCase converts snake_case to camelCase
+func toCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 || b.Len() == 0 {
+			b.WriteString(part)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
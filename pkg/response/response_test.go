@@ -0,0 +1,174 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ladderseeker/gin-crud-starter/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+func sampleUserResponse() model.UserResponse {
+	user := &model.User{
+		ID:        1,
+		Name:      "John Doe",
+		Email:     "john@example.com",
+		Role:      "user",
+		Active:    true,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	return user.ToResponse()
+}
+
+func keysOf(t *testing.T, value any) []string {
+	fields, ok := value.(map[string]any)
+	assert.True(t, ok)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestTransformUserResponse(t *testing.T) {
+	testCases := []struct {
+		name         string
+		convention   string
+		expectedKeys []string
+	}{
+		{
+			name:         "Snake",
+			convention:   KeyCaseSnake,
+			expectedKeys: []string{"id", "name", "email", "role", "active", "created_at", "updated_at"},
+		},
+		{
+			name:         "Camel",
+			convention:   KeyCaseCamel,
+			expectedKeys: []string{"id", "name", "email", "role", "active", "createdAt", "updatedAt"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Transform(sampleUserResponse(), tc.convention)
+
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedKeys, keysOf(t, result))
+		})
+	}
+}
+
+func TestTransformNested(t *testing.T) {
+	result, err := Transform([]map[string]any{{"userID": map[string]any{"createdAt": 1}}}, KeyCaseSnake)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"user_id": map[string]any{"created_at": json.Number("1")}}}, result)
+}
+
+func TestJSONAppliesKeyCase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetKeyCase(KeyCaseCamel)
+	defer SetKeyCase(KeyCaseDefault)
+
+	// Write a response through the helper
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	JSON(ctx, http.StatusOK, sampleUserResponse())
+
+	// Assert keys were rewritten
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, body, "createdAt")
+	assert.Equal(t, "2024-01-02T03:04:05Z", body["createdAt"])
+}
+
+func TestSendNegotiatesFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expected := sampleUserResponse()
+
+	send := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != "" {
+			ctx.Request.Header.Set("Accept", accept)
+		}
+		Send(ctx, http.StatusOK, expected)
+		return w
+	}
+
+	// JSON by default
+	w := send("")
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var fromJSON model.UserResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fromJSON))
+	assert.Equal(t, expected, fromJSON)
+
+	// MsgPack when requested
+	w = send("application/msgpack")
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/msgpack")
+
+	var fromMsgPack map[string]any
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+	assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), handle).Decode(&fromMsgPack))
+	assert.EqualValues(t, expected.ID, fromMsgPack["id"])
+	assert.Equal(t, expected.Email, fromMsgPack["email"])
+	assert.Equal(t, expected.Active, fromMsgPack["active"])
+
+	// Timestamps are RFC3339 strings as in JSON, not Go binary encodings
+	createdAt, ok := fromMsgPack["created_at"].(string)
+	assert.True(t, ok, "created_at decoded as %T", fromMsgPack["created_at"])
+	parsed, err := time.Parse(time.RFC3339, createdAt)
+	assert.NoError(t, err)
+	assert.True(t, expected.CreatedAt.Equal(parsed))
+}
+
+func TestSendOmitEmpty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A response with zero-value fields
+	user := sampleUserResponse()
+	user.Name = ""
+	user.Active = false
+	payload := gin.H{"user": user, "tags": []string{}, "count": 0, "items": []any{0, ""}}
+
+	send := func(url, prefer string) string {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		if prefer != "" {
+			ctx.Request.Header.Set("Prefer", prefer)
+		}
+		Send(ctx, http.StatusOK, payload)
+		return w.Body.String()
+	}
+
+	full := `{
+		"user": {"id":1,"name":"","email":"john@example.com","role":"user","active":false,
+			"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"},
+		"tags": [], "count": 0, "items": [0, ""]
+	}`
+	omitted := `{
+		"user": {"id":1,"email":"john@example.com","role":"user",
+			"created_at":"2024-01-02T03:04:05Z","updated_at":"2024-01-02T03:04:05Z"},
+		"items": [0, ""]
+	}`
+
+	// Every field is serialized by default
+	assert.JSONEq(t, full, send("/", ""))
+	assert.JSONEq(t, full, send("/?omit_empty=false", "omit-empty"))
+
+	// Empty fields are dropped on request
+	assert.JSONEq(t, omitted, send("/?omit_empty=true", ""))
+	assert.JSONEq(t, omitted, send("/", "respond-async, omit-empty"))
+}
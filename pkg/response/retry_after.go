@@ -0,0 +1,30 @@
+package response
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+)
+
+// HeaderRetryAfter tells clients how many seconds to wait before retrying
+const HeaderRetryAfter = "Retry-After"
+
+// DefaultRetryAfter is advertised when a 429 or 503 error carries no more specific delay
+const DefaultRetryAfter = time.Second
+
+// SetRetryAfter sets Retry-After on header for 429 and 503 errors, in whole seconds rounded up.
+// Other errors are left alone.
+func SetRetryAfter(header http.Header, appErr *apperrors.AppError) {
+	if appErr.StatusCode != http.StatusTooManyRequests && appErr.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+
+	delay := appErr.RetryAfter
+	if delay <= 0 {
+		delay = DefaultRetryAfter
+	}
+	seconds := (delay + time.Second - 1) / time.Second
+	header.Set(HeaderRetryAfter, strconv.FormatInt(int64(seconds), 10))
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	apperrors "github.com/ladderseeker/gin-crud-starter/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetRetryAfter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      *apperrors.AppError
+		expected string
+	}{
+		{name: "RateLimited", err: apperrors.NewRateLimitedError("Too many requests", nil).WithRetryAfter(45 * time.Second), expected: "45"},
+		{name: "RoundsUp", err: apperrors.NewServiceUnavailableError("Down", nil).WithRetryAfter(1500 * time.Millisecond), expected: "2"},
+		{name: "DefaultsWithoutDelay", err: apperrors.NewTimeoutError("Slow", nil), expected: "1"},
+		{name: "ElapsedDelay", err: apperrors.NewServiceUnavailableError("Down", nil).WithRetryAfter(-time.Second), expected: "1"},
+		{name: "OtherStatus", err: apperrors.NewResourceNotFoundError("Missing", nil, nil).WithRetryAfter(time.Minute), expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			SetRetryAfter(header, tc.err)
+
+			assert.Equal(t, tc.expected, header.Get(HeaderRetryAfter))
+		})
+	}
+}
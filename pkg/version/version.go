@@ -0,0 +1,35 @@
+// Package version reports the build of the running binary.
+// The variables are set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/ladderseeker/gin-crud-starter/pkg/version.Version=1.2.0 -X github.com/ladderseeker/gin-crud-starter/pkg/version.Commit=$(git rev-parse HEAD)"
+package version
+
+// Build metadata injected via ldflags
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// Get returns the build metadata, falling back to the defaults for values left empty
+func Get() Info {
+	return Info{
+		Version:   valueOr(Version, "dev"),
+		Commit:    valueOr(Commit, "unknown"),
+		BuildDate: valueOr(BuildDate, "unknown"),
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,18 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	// Defaults apply when nothing is injected
+	assert.Equal(t, Info{Version: "dev", Commit: "unknown", BuildDate: "unknown"}, Get())
+
+	// Empty injected values also fall back
+	original := Version
+	Version = ""
+	t.Cleanup(func() { Version = original })
+	assert.Equal(t, "dev", Get().Version)
+}